@@ -53,6 +53,10 @@ func main() {
 	if bizDate == "" {
 		bizDate = lastTradingDay(now).Format("2006-01-02")
 	}
+	bizDay, err := nepse.ParseDate(bizDate)
+	if err != nil {
+		log.Fatalf("Invalid -date: %v", err)
+	}
 
 	// Track security ID for later use
 	var securityID int32
@@ -72,7 +76,7 @@ func main() {
 			statusColor = green
 		}
 		printKV("Market", fmt.Sprintf("%s%s%s", statusColor, status.IsOpen, reset))
-		printKV("As Of", status.AsOf)
+		printKV("As Of", status.AsOf.String())
 	}
 
 	// Market Summary
@@ -400,7 +404,7 @@ func main() {
 
 	// Today's Prices
 	printSubSection(fmt.Sprintf("Today's Prices (%s)", bizDate))
-	if prices, err := client.TodaysPrices(ctx, bizDate); err != nil {
+	if prices, err := client.TodaysPrices(ctx, bizDay); err != nil {
 		printError("TodaysPrices", err)
 	} else if len(prices) == 0 {
 		printDim("No price data available (market closed or no trades on this date)")
@@ -419,7 +423,8 @@ func main() {
 	// Price History
 	if securityID != 0 {
 		printSubSection(fmt.Sprintf("Price History: %s (%s to %s)", symbol, startDate, endDate))
-		if hist, err := client.PriceHistory(ctx, securityID, startDate, endDate); err != nil {
+		history := nepse.DateRange{From: nepse.DateOf(now.AddDate(0, -1, 0)), To: nepse.DateOf(now)}
+		if hist, err := client.PriceHistory(ctx, securityID, history); err != nil {
 			printError("PriceHistory", err)
 		} else {
 			printKV("Data Points", fmt.Sprintf("%d trading days", len(hist)))
@@ -503,7 +508,7 @@ func main() {
 			printSubSection(fmt.Sprintf("Floorsheet: %s (%s)", symbol, bizDate))
 			// Note: NEPSE has blocked the company-specific floorsheet endpoint (returns 403).
 			// This is expected to fail. Use FloorSheet() for general floorsheet data instead.
-			if fs, err := client.FloorSheetOf(ctx, securityID, bizDate); err != nil {
+			if fs, err := client.FloorSheetOf(ctx, securityID, bizDay); err != nil {
 				printError("FloorSheetOf", err)
 				printDim("(This endpoint is blocked by NEPSE - expected behavior)")
 			} else {
@@ -575,7 +580,7 @@ func main() {
 				if len(g.Data) > 0 {
 					fmt.Printf("    %s%-20s %12s%s\n", dim, "Timestamp", "Value", reset)
 					for _, d := range g.Data[:min(5, len(g.Data))] {
-						fmt.Printf("    %-20d %12.2f\n", d.Timestamp, d.Value)
+						fmt.Printf("    %-20s %12.2f\n", d.Timestamp, d.Value)
 					}
 					if len(g.Data) > 5 {
 						printDim(fmt.Sprintf("... and %d more", len(g.Data)-5))
@@ -0,0 +1,38 @@
+// Command selftest checks NEPSE authentication end to end and prints which
+// stage fails: token fetch, token decoding, an authenticated GET, payload ID
+// computation, or an authenticated POST.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/voidarchive/go-nepse"
+)
+
+func main() {
+	if !run() {
+		os.Exit(1)
+	}
+}
+
+func run() bool {
+	opts := nepse.DefaultOptions()
+	opts.TLSVerification = false
+
+	client, err := nepse.NewClient(opts)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	report, err := client.AuthSelfTest(ctx)
+	fmt.Print(report)
+	return err == nil
+}
@@ -0,0 +1,105 @@
+package nepse
+
+import (
+	"context"
+	"iter"
+)
+
+// API is the full set of NEPSE data methods implemented by [*Client]. Code
+// that takes an API instead of a *Client can be tested against the fake in
+// the nepsefake package. Depend on one of the smaller interfaces when only
+// part of the API is used.
+//
+// nepsefake is generated from this file; run go generate ./nepsefake after
+// changing it.
+type API interface {
+	MarketData
+	Fundamentals
+	Graphs
+}
+
+// MarketData covers market-wide statistics, securities, prices, order books,
+// and floor sheets.
+type MarketData interface {
+	MarketSummary(ctx context.Context) (*MarketSummary, error)
+	MarketStatus(ctx context.Context) (*MarketStatus, error)
+	NepseIndex(ctx context.Context) (*NepseIndex, error)
+	SubIndices(ctx context.Context) ([]SubIndex, error)
+	LiveMarket(ctx context.Context) ([]LiveMarketEntry, error)
+	SupplyDemand(ctx context.Context) (*SupplyDemandData, error)
+
+	// Top lists.
+	TopGainers(ctx context.Context) ([]TopGainerLoserEntry, error)
+	TopLosers(ctx context.Context) ([]TopGainerLoserEntry, error)
+	TopTenTrade(ctx context.Context) ([]TopTradeEntry, error)
+	TopTenTransaction(ctx context.Context) ([]TopTransactionEntry, error)
+	TopTenTurnover(ctx context.Context) ([]TopTurnoverEntry, error)
+
+	// Prices and order books.
+	TodaysPrices(ctx context.Context, businessDate Date) ([]TodayPrice, error)
+	PriceHistory(ctx context.Context, securityID int32, r DateRange) ([]PriceHistory, error)
+	PriceHistoryBySymbol(ctx context.Context, symbol string, r DateRange) ([]PriceHistory, error)
+	PriceHistoryRange(ctx context.Context, securityID int32, r DateRange, opts *PriceHistoryOptions) ([]PriceHistory, error)
+	MarketDepth(ctx context.Context, securityID int32) (*MarketDepth, error)
+	MarketDepthBySymbol(ctx context.Context, symbol string) (*MarketDepth, error)
+
+	// Securities and companies.
+	Securities(ctx context.Context) ([]Security, error)
+	Companies(ctx context.Context) ([]Company, error)
+	Company(ctx context.Context, securityID int32) (*CompanyDetails, error)
+	CompanyBySymbol(ctx context.Context, symbol string) (*CompanyDetails, error)
+	SecurityDetail(ctx context.Context, securityID int32) (*SecurityDetail, error)
+	SecurityDetailBySymbol(ctx context.Context, symbol string) (*SecurityDetail, error)
+	DebugSecurityDetailRaw(ctx context.Context, securityID int32) ([]byte, error)
+	SectorScrips(ctx context.Context) (SectorScrips, error)
+	FindSecurity(ctx context.Context, securityID int32) (*Security, error)
+	FindSecurityBySymbol(ctx context.Context, symbol string) (*Security, error)
+
+	// Floor sheets.
+	FloorSheet(ctx context.Context) ([]FloorSheetEntry, error)
+	FloorSheetSeq(ctx context.Context, opts *PageOptions) iter.Seq2[FloorSheetEntry, error]
+	FloorSheetOf(ctx context.Context, securityID int32, businessDate Date) ([]FloorSheetEntry, error)
+	FloorSheetOfSeq(ctx context.Context, securityID int32, businessDate Date, opts *PageOptions) iter.Seq2[FloorSheetEntry, error]
+	FloorSheetBySymbol(ctx context.Context, symbol string, businessDate Date) ([]FloorSheetEntry, error)
+}
+
+// Fundamentals covers company profiles, boards, corporate actions, reports,
+// and dividends.
+type Fundamentals interface {
+	CompanyProfile(ctx context.Context, securityID int32) (*CompanyProfile, error)
+	CompanyProfileBySymbol(ctx context.Context, symbol string) (*CompanyProfile, error)
+	BoardOfDirectors(ctx context.Context, securityID int32) ([]BoardMember, error)
+	BoardOfDirectorsBySymbol(ctx context.Context, symbol string) ([]BoardMember, error)
+	CorporateActions(ctx context.Context, securityID int32) ([]CorporateAction, error)
+	CorporateActionsBySymbol(ctx context.Context, symbol string) ([]CorporateAction, error)
+	Reports(ctx context.Context, securityID int32) ([]Report, error)
+	ReportsBySymbol(ctx context.Context, symbol string) ([]Report, error)
+	Dividends(ctx context.Context, securityID int32) ([]Dividend, error)
+	DividendsBySymbol(ctx context.Context, symbol string) ([]Dividend, error)
+}
+
+// Graphs covers intraday index and security graphs.
+type Graphs interface {
+	DailyIndexGraph(ctx context.Context, indexType IndexType) (*GraphResponse, error)
+	DailyNepseIndexGraph(ctx context.Context) (*GraphResponse, error)
+	DailySensitiveIndexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyFloatIndexGraph(ctx context.Context) (*GraphResponse, error)
+	DailySensitiveFloatIndexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyBankSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyDevelopmentBankSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyFinanceSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyHotelTourismSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyHydroSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyInvestmentSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyLifeInsuranceSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyManufacturingSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyMicrofinanceSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyMutualfundSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyNonLifeInsuranceSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyOthersSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyTradingSubindexGraph(ctx context.Context) (*GraphResponse, error)
+	DailyScripGraph(ctx context.Context, securityID int32) (*GraphResponse, error)
+	DailyScripGraphBySymbol(ctx context.Context, symbol string) (*GraphResponse, error)
+}
+
+var _ API = (*Client)(nil)
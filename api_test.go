@@ -0,0 +1,34 @@
+package nepse
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+// TestAPI_CoversClient checks that every exported Client method declared in
+// the data files is part of API, so new endpoints reach nepsefake.
+func TestAPI_CoversClient(t *testing.T) {
+	api := reflect.TypeFor[API]()
+	fset := token.NewFileSet()
+	for _, name := range []string{"market.go", "fundamentals.go", "graphs.go"} {
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || !fn.Name.IsExported() {
+				continue
+			}
+			if star, ok := fn.Recv.List[0].Type.(*ast.StarExpr); !ok || star.X.(*ast.Ident).Name != "Client" {
+				continue
+			}
+			if _, ok := api.MethodByName(fn.Name.Name); !ok {
+				t.Errorf("%s: Client.%s is missing from API", name, fn.Name.Name)
+			}
+		}
+	}
+}
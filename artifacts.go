@@ -0,0 +1,90 @@
+package nepse
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/voidarchive/go-nepse/internal/auth"
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+// authArtifacts are the css.wasm module and payload table a client runs with.
+type authArtifacts struct {
+	module         []byte // nil means the embedded css.wasm
+	table          *payload.Table
+	tokenVectors   []TokenVector
+	payloadVectors []PayloadVector
+}
+
+// loadAuthArtifacts reads any replacement artifacts named in options. A
+// replaced artifact must come with its own vectors: the defaults describe the
+// built-in artifacts, and a rotated table that changes only entries they do
+// not cover would pass them by accident.
+func loadAuthArtifacts(options *Options) (*authArtifacts, error) {
+	a := &authArtifacts{
+		table:          payload.DefaultTable(),
+		tokenVectors:   options.TokenVectors,
+		payloadVectors: options.PayloadVectors,
+	}
+	if a.tokenVectors == nil {
+		a.tokenVectors = auth.DefaultTokenVectors()
+	}
+	if a.payloadVectors == nil {
+		a.payloadVectors = payload.DefaultVectors()
+	}
+
+	module, err := readArtifact(options.WASMModule, options.WASMModulePath)
+	if err != nil {
+		return nil, NewInvalidClientRequestError(fmt.Sprintf("failed to read WASM module: %v", err))
+	}
+	if module != nil && options.TokenParser == TokenParserNative {
+		return nil, NewInvalidClientRequestError("a replacement WASM module cannot be used with TokenParserNative")
+	}
+	if module != nil && len(options.TokenVectors) == 0 {
+		return nil, NewInvalidClientRequestError("a replacement WASM module requires TokenVectors captured for it")
+	}
+	a.module = module
+
+	raw, err := readArtifact(options.PayloadTable, options.PayloadTablePath)
+	if err != nil {
+		return nil, NewInvalidClientRequestError(fmt.Sprintf("failed to read payload table: %v", err))
+	}
+	if raw != nil {
+		if len(options.PayloadVectors) == 0 {
+			return nil, NewInvalidClientRequestError("a replacement payload table requires PayloadVectors captured for it")
+		}
+		table, err := payload.ParseTable(bytes.NewReader(raw))
+		if err != nil {
+			return nil, NewInvalidClientRequestError(err.Error())
+		}
+		a.table = table
+	}
+	return a, nil
+}
+
+// readArtifact reads r, or the file at path when r is nil.
+// It returns nil when neither is set.
+func readArtifact(r io.Reader, path string) ([]byte, error) {
+	switch {
+	case r != nil:
+		return io.ReadAll(r)
+	case path != "":
+		return os.ReadFile(path)
+	default:
+		return nil, nil
+	}
+}
+
+// verify runs the test vectors so a stale or corrupt artifact fails at startup
+// instead of surfacing later as 401s or rejected graph payloads.
+func (a *authArtifacts) verify(m *auth.Manager) error {
+	if err := m.Verify(a.tokenVectors); err != nil {
+		return NewInternalError("token parser failed validation", err)
+	}
+	if err := a.table.Verify(a.payloadVectors); err != nil {
+		return NewInternalError("payload table failed validation", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package nepse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/voidarchive/go-nepse/internal/auth"
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+// tableJSON encodes the built-in payload table with the entries in edits replaced.
+func tableJSON(t *testing.T, edits map[int]int) string {
+	t.Helper()
+	table := payload.DefaultTable()
+	for i, v := range edits {
+		table[i] = v
+	}
+	b, err := json.Marshal(table[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestNewClient_ReplacementArtifacts(t *testing.T) {
+	module, err := readArtifact(nil, "internal/auth/css.wasm")
+	if err != nil {
+		t.Fatalf("read css.wasm: %v", err)
+	}
+
+	client, err := NewClient(&Options{
+		Config:         DefaultConfig(),
+		WASMModule:     bytes.NewReader(module),
+		TokenVectors:   auth.DefaultTokenVectors(),
+		PayloadTable:   strings.NewReader(tableJSON(t, map[int]int{61: 1000})),
+		PayloadVectors: []PayloadVector{{StatusID: 61, Day: 1, Base: 1063}},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if got := client.payload.Base(61, 1); got != 1063 {
+		t.Errorf("Base(61, 1) = %d, want 1063 from replacement table", got)
+	}
+}
+
+func TestNewClient_ArtifactValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		want    error
+	}{
+		{"module not wasm", Options{WASMModule: strings.NewReader("not wasm"), TokenVectors: auth.DefaultTokenVectors()}, ErrInternal},
+		{"module without vectors", Options{WASMModulePath: "internal/auth/css.wasm"}, ErrInvalidClientRequest},
+		{"missing module file", Options{WASMModulePath: "testdata/missing.wasm"}, ErrInvalidClientRequest},
+		{"module with native parser", Options{WASMModulePath: "internal/auth/css.wasm", TokenVectors: auth.DefaultTokenVectors(), TokenParser: TokenParserNative}, ErrInvalidClientRequest},
+		{"token vector mismatch", Options{TokenVectors: []TokenVector{{Salts: [5]int{1, 2, 3, 4, 5}, Access: []int{1}, Refresh: []int{2}}}}, ErrInternal},
+		{"short table", Options{PayloadTable: strings.NewReader("[1, 2, 3]"), PayloadVectors: payload.DefaultVectors()}, ErrInvalidClientRequest},
+		{"table without vectors", Options{PayloadTable: strings.NewReader(tableJSON(t, map[int]int{61: 1000}))}, ErrInvalidClientRequest},
+		{"table failing its vectors", Options{PayloadTable: strings.NewReader(tableJSON(t, map[int]int{61: 1000})), PayloadVectors: payload.DefaultVectors()}, ErrInternal},
+		{"missing table file", Options{PayloadTablePath: "testdata/missing.json"}, ErrInvalidClientRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.options
+			opts.Config = DefaultConfig()
+			client, err := NewClient(&opts)
+			if err == nil {
+				client.Close()
+				t.Fatal("expected NewClient to fail")
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
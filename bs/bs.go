@@ -0,0 +1,277 @@
+// Package bs converts between the Gregorian (AD) calendar and Bikram Sambat
+// (BS), the Nepali calendar NEPSE uses for fiscal years and quarters.
+//
+// BS month lengths follow no formula, so conversions use a month-length
+// table embedded from calendar.txt covering [MinYear] through [MaxYear].
+// Dates outside it return [ErrOutOfRange].
+//
+// Fiscal years run from Shrawan 1 to the end of Ashadh. [FiscalYear] and the
+// helpers in fiscal.go map NEPSE's FinancialYear and QuarterMaster onto
+// [nepse.DateRange] values, so reports and dividends can be lined up with
+// price history.
+package bs
+
+import (
+	"bufio"
+	"cmp"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+//go:embed calendar.txt
+var calendarTxt string
+
+// Range of BS years the embedded table covers.
+const (
+	MinYear = 2000
+	MaxYear = 2090
+)
+
+// ErrOutOfRange is returned for dates outside the embedded table.
+var ErrOutOfRange = errors.New("bs: date outside supported range")
+
+// epoch is Baisakh 1, MinYear BS, as a civil AD date.
+var epoch = time.Date(1943, time.April, 14, 0, 0, 0, 0, time.UTC)
+
+// calendar is the parsed month-length table.
+type calendar struct {
+	monthDays [MaxYear - MinYear + 1][12]int
+	yearStart [MaxYear - MinYear + 2]int // days from epoch to Baisakh 1 of each year, plus one past the end
+}
+
+var cal = mustLoadCalendar(calendarTxt)
+
+func mustLoadCalendar(src string) *calendar {
+	c, err := loadCalendar(src)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// loadCalendar parses the month-length table in calendar.txt's format.
+func loadCalendar(src string) (*calendar, error) {
+	c := new(calendar)
+	next := MinYear
+	sc := bufio.NewScanner(strings.NewReader(src))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 13 {
+			return nil, fmt.Errorf("bs: calendar line %q: want a year and 12 month lengths", line)
+		}
+		year, err := strconv.Atoi(fields[0])
+		if err != nil || year != next {
+			return nil, fmt.Errorf("bs: calendar line %q: want year %d", line, next)
+		}
+		i := year - MinYear
+		total := 0
+		for m, f := range fields[1:] {
+			days, err := strconv.Atoi(f)
+			if err != nil || days < 29 || days > 32 {
+				return nil, fmt.Errorf("bs: calendar %d month %d: invalid length %q", year, m+1, f)
+			}
+			c.monthDays[i][m] = days
+			total += days
+		}
+		c.yearStart[i+1] = c.yearStart[i] + total
+		next++
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if next != MaxYear+1 {
+		return nil, fmt.Errorf("bs: calendar ends at %d, want %d", next-1, MaxYear)
+	}
+	return c, nil
+}
+
+// Month is a BS month, Baisakh (1) through Chaitra (12).
+type Month int
+
+// BS months.
+const (
+	Baisakh Month = 1 + iota
+	Jestha
+	Ashadh
+	Shrawan
+	Bhadra
+	Ashwin
+	Kartik
+	Mangsir
+	Poush
+	Magh
+	Falgun
+	Chaitra
+)
+
+var (
+	monthNames       = [...]string{"Baisakh", "Jestha", "Ashadh", "Shrawan", "Bhadra", "Ashwin", "Kartik", "Mangsir", "Poush", "Magh", "Falgun", "Chaitra"}
+	monthNamesNepali = [...]string{"बैशाख", "जेठ", "असार", "साउन", "भदौ", "असोज", "कात्तिक", "मंसिर", "पुस", "माघ", "फागुन", "चैत"}
+)
+
+// String returns the English name of m, such as "Shrawan".
+func (m Month) String() string {
+	if m < Baisakh || m > Chaitra {
+		return "Month(" + strconv.Itoa(int(m)) + ")"
+	}
+	return monthNames[m-1]
+}
+
+// Nepali returns the Nepali name of m, such as "साउन".
+func (m Month) Nepali() string {
+	if m < Baisakh || m > Chaitra {
+		return m.String()
+	}
+	return monthNamesNepali[m-1]
+}
+
+// DaysIn returns the number of days in month m of BS year year.
+func DaysIn(year int, m Month) (int, error) {
+	if year < MinYear || year > MaxYear {
+		return 0, fmt.Errorf("%w: year %d", ErrOutOfRange, year)
+	}
+	if m < Baisakh || m > Chaitra {
+		return 0, fmt.Errorf("bs: invalid month %d", m)
+	}
+	return cal.monthDays[year-MinYear][m-1], nil
+}
+
+// Date is a BS calendar date. Create one with [New], [FromAD], or [Parse]
+// to be sure it exists.
+type Date struct {
+	Year  int
+	Month Month
+	Day   int
+}
+
+// New returns the BS date year-month-day, or an error if the table has no
+// such date.
+func New(year int, month Month, day int) (Date, error) {
+	n, err := DaysIn(year, month)
+	if err != nil {
+		return Date{}, err
+	}
+	if day < 1 || day > n {
+		return Date{}, fmt.Errorf("bs: %s %d has %d days, not %d", month, year, n, day)
+	}
+	return Date{year, month, day}, nil
+}
+
+// FromAD returns the BS date of d.
+func FromAD(d nepse.Date) (Date, error) {
+	if d.IsZero() {
+		return Date{}, errors.New("bs: zero date")
+	}
+	y, m, day := d.Time().Date()
+	days := int(time.Date(y, m, day, 0, 0, 0, 0, time.UTC).Sub(epoch).Hours() / 24)
+	if days < 0 || days >= cal.yearStart[len(cal.yearStart)-1] {
+		return Date{}, fmt.Errorf("%w: %s", ErrOutOfRange, d)
+	}
+
+	i := 0
+	for cal.yearStart[i+1] <= days {
+		i++
+	}
+	days -= cal.yearStart[i]
+	month := 0
+	for days >= cal.monthDays[i][month] {
+		days -= cal.monthDays[i][month]
+		month++
+	}
+	return Date{MinYear + i, Month(month + 1), days + 1}, nil
+}
+
+// Today returns today's BS date in Nepal.
+func Today() (Date, error) {
+	return FromAD(nepse.DateOf(time.Now()))
+}
+
+// AD returns the AD date of d.
+func (d Date) AD() (nepse.Date, error) {
+	if _, err := New(d.Year, d.Month, d.Day); err != nil {
+		return nepse.Date{}, err
+	}
+	i := d.Year - MinYear
+	days := cal.yearStart[i] + d.Day - 1
+	for m := range int(d.Month) - 1 {
+		days += cal.monthDays[i][m]
+	}
+	return nepse.NewDate(epoch.Year(), epoch.Month(), epoch.Day()+days), nil
+}
+
+// Compare returns -1, 0, or +1 as d is before, equal to, or after u.
+func (d Date) Compare(u Date) int {
+	if c := cmp.Compare(d.Year, u.Year); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(d.Month, u.Month); c != 0 {
+		return c
+	}
+	return cmp.Compare(d.Day, u.Day)
+}
+
+// String returns d as "2080-04-01".
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+// English returns d as "1 Shrawan 2080".
+func (d Date) English() string {
+	return fmt.Sprintf("%d %s %d", d.Day, d.Month, d.Year)
+}
+
+// Nepali returns d in Devanagari as "२०८० साउन १".
+func (d Date) Nepali() string {
+	return Digits(fmt.Sprintf("%d %s %d", d.Year, d.Month.Nepali(), d.Day))
+}
+
+// Parse parses a BS date written year, month, day with "-", "/", or "."
+// between them, in ASCII or Devanagari digits, such as "2080-04-01" or
+// "२०८०/४/१".
+func Parse(s string) (Date, error) {
+	parts := strings.FieldsFunc(asciiDigits(strings.TrimSpace(s)), func(r rune) bool {
+		return r == '-' || r == '/' || r == '.'
+	})
+	if len(parts) != 3 {
+		return Date{}, fmt.Errorf("bs: parse %q: want year, month, and day", s)
+	}
+	var n [3]int
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return Date{}, fmt.Errorf("bs: parse %q: %q is not a number", s, p)
+		}
+		n[i] = v
+	}
+	return New(n[0], Month(n[1]), n[2])
+}
+
+// Digits replaces the ASCII digits in s with Devanagari digits.
+func Digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return '०' + (r - '0')
+		}
+		return r
+	}, s)
+}
+
+// asciiDigits replaces the Devanagari digits in s with ASCII digits.
+func asciiDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '०' && r <= '९' {
+			return '0' + (r - '०')
+		}
+		return r
+	}, s)
+}
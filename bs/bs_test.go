@@ -0,0 +1,114 @@
+package bs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+// Published new year and fiscal year start dates.
+var knownDates = []struct {
+	bs Date
+	ad nepse.Date
+}{
+	{Date{2000, Baisakh, 1}, nepse.NewDate(1943, 4, 14)},
+	{Date{2050, Baisakh, 1}, nepse.NewDate(1993, 4, 13)},
+	{Date{2060, Baisakh, 1}, nepse.NewDate(2003, 4, 14)},
+	{Date{2073, Baisakh, 1}, nepse.NewDate(2016, 4, 13)},
+	{Date{2077, Baisakh, 1}, nepse.NewDate(2020, 4, 13)},
+	{Date{2080, Baisakh, 1}, nepse.NewDate(2023, 4, 14)},
+	{Date{2081, Baisakh, 1}, nepse.NewDate(2024, 4, 13)},
+	{Date{2082, Baisakh, 1}, nepse.NewDate(2025, 4, 14)},
+	{Date{2077, Shrawan, 1}, nepse.NewDate(2020, 7, 16)},
+	{Date{2078, Shrawan, 1}, nepse.NewDate(2021, 7, 16)},
+	{Date{2079, Shrawan, 1}, nepse.NewDate(2022, 7, 17)},
+	{Date{2080, Shrawan, 1}, nepse.NewDate(2023, 7, 17)},
+	{Date{2081, Shrawan, 1}, nepse.NewDate(2024, 7, 16)},
+	{Date{2082, Shrawan, 1}, nepse.NewDate(2025, 7, 17)},
+	{Date{2081, Chaitra, 31}, nepse.NewDate(2025, 4, 13)},
+}
+
+func TestKnownDates(t *testing.T) {
+	for _, tt := range knownDates {
+		ad, err := tt.bs.AD()
+		if err != nil || ad != tt.ad {
+			t.Errorf("%v.AD() = %v, %v; want %v", tt.bs, ad, err, tt.ad)
+		}
+		got, err := FromAD(tt.ad)
+		if err != nil || got != tt.bs {
+			t.Errorf("FromAD(%v) = %v, %v; want %v", tt.ad, got, err, tt.bs)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	ad := nepse.NewDate(1943, 4, 14)
+	prev := Date{}
+	for {
+		d, err := FromAD(ad)
+		if errors.Is(err, ErrOutOfRange) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("FromAD(%v): %v", ad, err)
+		}
+		if prev != (Date{}) && d.Compare(prev) <= 0 {
+			t.Fatalf("FromAD(%v) = %v, not after %v", ad, d, prev)
+		}
+		back, err := d.AD()
+		if err != nil || back != ad {
+			t.Fatalf("%v.AD() = %v, %v; want %v", d, back, err, ad)
+		}
+		prev, ad = d, ad.AddDays(1)
+	}
+	if prev.Year != MaxYear || prev.Month != Chaitra {
+		t.Errorf("last convertible date = %v, want the end of %d", prev, MaxYear)
+	}
+	if _, err := FromAD(nepse.NewDate(1943, 4, 13)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("day before the table: err = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	for _, d := range []Date{{2080, Baisakh, 32}, {2080, 13, 1}, {2080, Shrawan, 0}, {1999, Chaitra, 1}, {2091, Baisakh, 1}} {
+		if _, err := New(d.Year, d.Month, d.Day); err == nil {
+			t.Errorf("New(%v) succeeded, want error", d)
+		}
+	}
+}
+
+func TestParseAndFormat(t *testing.T) {
+	for _, s := range []string{"2080-04-01", "2080/4/1", "2080.04.01", "२०८०/०४/०१"} {
+		d, err := Parse(s)
+		if err != nil || d != (Date{2080, Shrawan, 1}) {
+			t.Errorf("Parse(%q) = %v, %v", s, d, err)
+		}
+	}
+	if _, err := Parse("2080-04"); err == nil {
+		t.Error("Parse accepted a date without a day")
+	}
+
+	d := Date{2080, Shrawan, 1}
+	if s := d.String(); s != "2080-04-01" {
+		t.Errorf("String = %q", s)
+	}
+	if s := d.English(); s != "1 Shrawan 2080" {
+		t.Errorf("English = %q", s)
+	}
+	if s := d.Nepali(); s != "२०८० साउन १" {
+		t.Errorf("Nepali = %q", s)
+	}
+}
+
+func TestCalendarFile(t *testing.T) {
+	short := strings.Replace(calendarTxt, "2090 30 32 31 32 31 30 30 30 29 30 30 30\n", "", 1)
+	if _, err := loadCalendar(short); err == nil {
+		t.Error("loadCalendar accepted a table missing its last year")
+	}
+	bad := strings.Replace(calendarTxt, "2080 31 32", "2080 31 33", 1)
+	if _, err := loadCalendar(bad); err == nil {
+		t.Error("loadCalendar accepted a 33-day month")
+	}
+}
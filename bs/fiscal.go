@@ -0,0 +1,179 @@
+package bs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+// FiscalYear is a Nepali fiscal year, named by the BS year it starts in:
+// FiscalYear(2080) runs from Shrawan 1, 2080 to the end of Ashadh 2081.
+type FiscalYear int
+
+// FiscalYearOf returns the fiscal year d falls in.
+func FiscalYearOf(d Date) FiscalYear {
+	if d.Month < Shrawan {
+		return FiscalYear(d.Year - 1)
+	}
+	return FiscalYear(d.Year)
+}
+
+// ParseFiscalYear parses a fiscal year name as NEPSE writes it, such as
+// "2080/2081", "2080/81", "2080-81", or "२०८०/८१". A lone year is taken as
+// the starting year.
+func ParseFiscalYear(s string) (FiscalYear, error) {
+	parts := strings.FieldsFunc(asciiDigits(strings.TrimSpace(s)), func(r rune) bool {
+		return r == '/' || r == '-'
+	})
+	if len(parts) < 1 || len(parts) > 2 {
+		return 0, fmt.Errorf("bs: parse fiscal year %q: want START/END", s)
+	}
+	start, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("bs: parse fiscal year %q: %q is not a year", s, parts[0])
+	}
+	if len(parts) == 2 {
+		end, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, fmt.Errorf("bs: parse fiscal year %q: %q is not a year", s, parts[1])
+		}
+		// A short end year, as in 2080/81, gives only the last digits.
+		if mod := pow10(len(parts[1])); mod < 10000 && end == (start+1)%mod {
+			end = start + 1
+		}
+		if end != start+1 {
+			return 0, fmt.Errorf("bs: parse fiscal year %q: end year does not follow start year", s)
+		}
+	}
+	if start < MinYear || start >= MaxYear {
+		return 0, fmt.Errorf("%w: fiscal year %q", ErrOutOfRange, s)
+	}
+	return FiscalYear(start), nil
+}
+
+func pow10(n int) int {
+	p := 1
+	for range n {
+		p *= 10
+	}
+	return p
+}
+
+// String returns fy as "2080/81".
+func (fy FiscalYear) String() string {
+	return fmt.Sprintf("%d/%02d", int(fy), (int(fy)+1)%100)
+}
+
+// Nepali returns fy in Devanagari as "२०८०/८१".
+func (fy FiscalYear) Nepali() string {
+	return Digits(fy.String())
+}
+
+// Range returns the AD dates fy covers.
+func (fy FiscalYear) Range() (nepse.DateRange, error) {
+	return bsRange(int(fy), Shrawan, int(fy)+1, Ashadh)
+}
+
+// Quarter returns the AD dates of quarter q (1-4) of fy. The first quarter
+// is Shrawan to Ashwin and the fourth is Baisakh to Ashadh of the next year.
+func (fy FiscalYear) Quarter(q int) (nepse.DateRange, error) {
+	if q < 1 || q > 4 {
+		return nepse.DateRange{}, fmt.Errorf("bs: invalid quarter %d", q)
+	}
+	year, first := int(fy), Shrawan+Month(3*(q-1))
+	if first > Chaitra {
+		year, first = year+1, first-12
+	}
+	return bsRange(year, first, year, first+2)
+}
+
+// bsRange returns the AD dates from the first day of fromMonth to the last
+// day of toMonth.
+func bsRange(fromYear int, fromMonth Month, toYear int, toMonth Month) (nepse.DateRange, error) {
+	last, err := DaysIn(toYear, toMonth)
+	if err != nil {
+		return nepse.DateRange{}, err
+	}
+	from, err := Date{fromYear, fromMonth, 1}.AD()
+	if err != nil {
+		return nepse.DateRange{}, err
+	}
+	to, err := Date{toYear, toMonth, last}.AD()
+	if err != nil {
+		return nepse.DateRange{}, err
+	}
+	return nepse.DateRange{From: from, To: to}, nil
+}
+
+// ParseQuarter returns the quarter number (1-4) named by a NEPSE
+// QuarterMaster, such as "First Quarter", "2nd Quarter", or "Q3".
+func ParseQuarter(name string) (int, error) {
+	s := strings.ToLower(strings.TrimSpace(name))
+	s = strings.TrimSpace(strings.TrimSuffix(s, "quarter"))
+	for q, names := range [][]string{
+		{"first", "1st", "q1", "1"},
+		{"second", "2nd", "q2", "2"},
+		{"third", "3rd", "q3", "3"},
+		{"fourth", "4th", "q4", "4"},
+	} {
+		for _, n := range names {
+			if s == n {
+				return q + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("bs: unknown quarter %q", name)
+}
+
+// FromFinancialYear returns the fiscal year NEPSE's FinancialYear names,
+// read from FYName or, failing that, FYNameNepali.
+func FromFinancialYear(fy *nepse.FinancialYear) (FiscalYear, error) {
+	if fy == nil {
+		return 0, errors.New("bs: no financial year")
+	}
+	year, err := ParseFiscalYear(fy.FYName)
+	if err != nil && fy.FYNameNepali != "" {
+		year, err = ParseFiscalYear(fy.FYNameNepali)
+	}
+	return year, err
+}
+
+// FinancialYearRange returns the AD dates a NEPSE FinancialYear covers.
+func FinancialYearRange(fy *nepse.FinancialYear) (nepse.DateRange, error) {
+	year, err := FromFinancialYear(fy)
+	if err != nil {
+		return nepse.DateRange{}, err
+	}
+	return year.Range()
+}
+
+// QuarterRange returns the AD dates of quarter q of fy.
+func QuarterRange(fy *nepse.FinancialYear, q *nepse.QuarterMaster) (nepse.DateRange, error) {
+	year, err := FromFinancialYear(fy)
+	if err != nil {
+		return nepse.DateRange{}, err
+	}
+	if q == nil {
+		return nepse.DateRange{}, errors.New("bs: no quarter")
+	}
+	n, err := ParseQuarter(q.QuarterName)
+	if err != nil {
+		return nepse.DateRange{}, err
+	}
+	return year.Quarter(n)
+}
+
+// ReportRange returns the AD dates a report covers: its quarter for
+// quarterly reports and its fiscal year otherwise.
+func ReportRange(r *nepse.Report) (nepse.DateRange, error) {
+	if r == nil || r.FiscalReport == nil {
+		return nepse.DateRange{}, errors.New("bs: report has no fiscal details")
+	}
+	if r.IsQuarterly() {
+		return QuarterRange(r.FiscalReport.FinancialYear, r.FiscalReport.QuarterMaster)
+	}
+	return FinancialYearRange(r.FiscalReport.FinancialYear)
+}
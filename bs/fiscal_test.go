@@ -0,0 +1,126 @@
+package bs
+
+import (
+	"testing"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+func TestParseFiscalYear(t *testing.T) {
+	for _, s := range []string{"2080/2081", "2080/81", "2080-81", "२०८०/८१", "२०८०/२०८१", " 2080 "} {
+		fy, err := ParseFiscalYear(s)
+		if err != nil || fy != 2080 {
+			t.Errorf("ParseFiscalYear(%q) = %v, %v; want 2080/81", s, fy, err)
+		}
+	}
+	for _, s := range []string{"", "2080/82", "2080/2080", "FY80", "1999/00"} {
+		if fy, err := ParseFiscalYear(s); err == nil {
+			t.Errorf("ParseFiscalYear(%q) = %v, want error", s, fy)
+		}
+	}
+	if s := FiscalYear(2080).String(); s != "2080/81" {
+		t.Errorf("String = %q", s)
+	}
+	if s := FiscalYear(2080).Nepali(); s != "२०८०/८१" {
+		t.Errorf("Nepali = %q", s)
+	}
+}
+
+func TestFiscalYearOf(t *testing.T) {
+	tests := []struct {
+		d    Date
+		want FiscalYear
+	}{
+		{Date{2080, Shrawan, 1}, 2080},
+		{Date{2081, Ashadh, 31}, 2080},
+		{Date{2081, Baisakh, 1}, 2080},
+		{Date{2080, Ashadh, 1}, 2079},
+	}
+	for _, tt := range tests {
+		if got := FiscalYearOf(tt.d); got != tt.want {
+			t.Errorf("FiscalYearOf(%v) = %v, want %v", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFiscalRanges(t *testing.T) {
+	fy := FiscalYear(2080)
+	r, err := fy.Range()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.From != nepse.NewDate(2023, 7, 17) || r.To != nepse.NewDate(2024, 7, 15) {
+		t.Errorf("Range = %v to %v, want 2023-07-17 to 2024-07-15", r.From, r.To)
+	}
+
+	// Quarters tile the year without gaps.
+	next := r.From
+	for q := 1; q <= 4; q++ {
+		qr, err := fy.Quarter(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if qr.From != next {
+			t.Errorf("quarter %d starts %v, want %v", q, qr.From, next)
+		}
+		next = qr.To.AddDays(1)
+	}
+	if next != r.To.AddDays(1) {
+		t.Errorf("quarters end %v, want %v", next.AddDays(-1), r.To)
+	}
+
+	if _, err := fy.Quarter(5); err == nil {
+		t.Error("Quarter(5) succeeded")
+	}
+}
+
+func TestParseQuarter(t *testing.T) {
+	tests := map[string]int{
+		"First Quarter":  1,
+		"second quarter": 2,
+		"3rd Quarter":    3,
+		"Q4":             4,
+	}
+	for name, want := range tests {
+		if got, err := ParseQuarter(name); err != nil || got != want {
+			t.Errorf("ParseQuarter(%q) = %d, %v; want %d", name, got, err, want)
+		}
+	}
+	if _, err := ParseQuarter("Annual"); err == nil {
+		t.Error("ParseQuarter(\"Annual\") succeeded")
+	}
+}
+
+func TestReportRange(t *testing.T) {
+	fy := &nepse.FinancialYear{FYName: "2080/2081", FYNameNepali: "२०८०/२०८१"}
+	quarterly := &nepse.Report{FiscalReport: &nepse.FiscalReport{
+		FinancialYear:    fy,
+		QuarterMaster:    &nepse.QuarterMaster{QuarterName: "Second Quarter"},
+		ReportTypeMaster: &nepse.ReportTypeMaster{ReportName: "Quarterly Report"},
+	}}
+	r, err := ReportRange(quarterly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := FiscalYear(2080).Quarter(2)
+	if r != want {
+		t.Errorf("quarterly ReportRange = %v, want %v", r, want)
+	}
+
+	annual := &nepse.Report{FiscalReport: &nepse.FiscalReport{
+		FinancialYear:    &nepse.FinancialYear{FYNameNepali: "२०८०/८१"},
+		ReportTypeMaster: &nepse.ReportTypeMaster{ReportName: "Annual Report"},
+	}}
+	r, err = ReportRange(annual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ = FiscalYear(2080).Range()
+	if r != want {
+		t.Errorf("annual ReportRange = %v, want %v", r, want)
+	}
+
+	if _, err := ReportRange(&nepse.Report{}); err == nil {
+		t.Error("ReportRange succeeded without fiscal details")
+	}
+}
@@ -0,0 +1,222 @@
+package nepse
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache stores successful API responses keyed by request method, endpoint, and body.
+// Implementations must be safe for concurrent use.
+//
+// Get should keep returning entries after ExpiresAt has passed; the client
+// decides whether an expired entry may still be served (see Options.CacheServeStale).
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// CacheEntry is a cached response body with its freshness window.
+type CacheEntry struct {
+	Body      []byte    `json:"body"`
+	StoredAt  time.Time `json:"storedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Fresh reports whether the entry is still within its TTL at now.
+func (e *CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// cacheKey builds the cache key for a request. Tokens never appear in keys.
+func cacheKey(method, endpoint string, body []byte) string {
+	if len(body) == 0 {
+		return method + " " + endpoint
+	}
+	return method + " " + endpoint + " " + string(body)
+}
+
+// cacheTTL returns the configured TTL for endpoint, or zero if it is not cached.
+func (c *Client) cacheTTL(endpoint string) time.Duration {
+	if c.options.Cache == nil || len(c.options.CacheTTL) == 0 {
+		return 0
+	}
+	return c.options.CacheTTL[c.config.Endpoints.fieldFor(endpoint)]
+}
+
+// cachedFetch serves key from the cache when fresh, otherwise calls fetch and
+// stores the result. With CacheServeStale set, an expired entry is returned
+// when fetch fails with a server error or timeout.
+func (c *Client) cachedFetch(key string, ttl time.Duration, fetch func() ([]byte, error)) ([]byte, error) {
+	if ttl <= 0 {
+		return fetch()
+	}
+
+	cache := c.options.Cache
+	entry, ok := cache.Get(key)
+	if ok && entry.Fresh(time.Now()) {
+		return entry.Body, nil
+	}
+
+	data, err := fetch()
+	if err != nil {
+		if ok && c.options.CacheServeStale && isStaleServable(err) {
+			return entry.Body, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	cache.Set(key, &CacheEntry{Body: data, StoredAt: now, ExpiresAt: now.Add(ttl)})
+	return data, nil
+}
+
+// isStaleServable reports whether err is an upstream failure for which a
+// stale cached response is preferable to an error.
+func isStaleServable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	return errors.Is(err, ErrInvalidServerResponse) || errors.Is(err, ErrNetworkError)
+}
+
+// MemoryCache is an in-memory LRU [Cache].
+type MemoryCache struct {
+	maxEntries int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns an LRU cache holding at most maxEntries responses.
+// If maxEntries is not positive, the cache is unbounded.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry for key and marks it as recently used.
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	m.ll.MoveToFront(el)
+	return el.Value.(*memoryCacheItem).entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entry if full.
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.items[key]; ok {
+		el.Value.(*memoryCacheItem).entry = entry
+		m.ll.MoveToFront(el)
+		return
+	}
+	m.items[key] = m.ll.PushFront(&memoryCacheItem{key: key, entry: entry})
+	if m.maxEntries > 0 && m.ll.Len() > m.maxEntries {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Delete removes key from the cache.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.items[key]; ok {
+		m.ll.Remove(el)
+		delete(m.items, key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+// DiskCache is a [Cache] that stores one JSON file per entry in a directory.
+// Entries survive process restarts, which makes stale responses available
+// even right after a deploy. Write failures are ignored; the cache is best-effort.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a cache rooted at dir, creating the directory if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get reads the entry for key from disk.
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Set writes the entry for key to disk atomically.
+func (d *DiskCache) Set(key string, entry *CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// Delete removes the entry for key from disk.
+func (d *DiskCache) Delete(key string) {
+	_ = os.Remove(d.path(key))
+}
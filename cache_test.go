@@ -0,0 +1,206 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEndpoints_FieldFor(t *testing.T) {
+	endpoints := DefaultEndpoints()
+
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"/api/nots/market-summary", "MarketSummary"},
+		{"/api/nots/security?nonDelisted=true", "SecurityList"},
+		{"/api/nots/security/131", "CompanyDetails"},
+		{"/api/nots/nepse-data/marketdepth/131", "MarketDepth"},
+		{"/api/nots/market/history/security/131?size=500&startDate=2025-01-01", "CompanyPriceHistory"},
+		{"/api/nots/nepse-data/floorsheet?size=500&sort=contractId,desc", "FloorSheet"},
+		{"/api/nots/graph/index/58", "GraphNepseIndex"},
+		{"/api/nots/market/graphdata/daily/131", "CompanyDailyGraph"},
+		{"/api/nots/unknown", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			if got := endpoints.fieldFor(tt.endpoint); got != tt.want {
+				t.Errorf("fieldFor(%q) = %q, want %q", tt.endpoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryCache_LRUEviction(t *testing.T) {
+	cache := NewMemoryCache(2)
+	entry := func(body string) *CacheEntry { return &CacheEntry{Body: []byte(body)} }
+
+	cache.Set("a", entry("1"))
+	cache.Set("b", entry("2"))
+	if _, ok := cache.Get("a"); !ok { // a is now most recently used
+		t.Fatal("expected a to be cached")
+	}
+	cache.Set("c", entry("3"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("expected a to survive eviction")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expected a to be deleted")
+	}
+}
+
+func TestDiskCache_RoundTrip(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache failed: %v", err)
+	}
+
+	expires := time.Now().Add(time.Minute).Truncate(time.Second)
+	cache.Set("GET /api/nots/market-summary", &CacheEntry{Body: []byte(`[1,2]`), ExpiresAt: expires})
+
+	got, ok := cache.Get("GET /api/nots/market-summary")
+	if !ok {
+		t.Fatal("expected entry on disk")
+	}
+	if string(got.Body) != `[1,2]` || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("unexpected entry %+v", got)
+	}
+
+	cache.Delete("GET /api/nots/market-summary")
+	if _, ok := cache.Get("GET /api/nots/market-summary"); ok {
+		t.Error("expected entry to be deleted")
+	}
+}
+
+func TestClient_ResponseCache(t *testing.T) {
+	var summaryCalls atomic.Int32
+	var failing atomic.Bool
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tokenResponse())
+		case "/api/nots/market-summary":
+			summaryCalls.Add(1)
+			if failing.Load() {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode([]MarketSummaryItem{
+				{Detail: "Total Turnover Rs:", Value: 1000},
+			})
+		case "/api/nots/nepse-data/market-open":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(MarketStatus{IsOpen: "OPEN"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	newClient := func(serveStale bool) *Client {
+		client, err := NewClient(&Options{
+			BaseURL:         server.URL,
+			HTTPTimeout:     5 * time.Second,
+			MaxRetries:      0,
+			Cache:           NewMemoryCache(16),
+			CacheTTL:        map[string]time.Duration{"MarketSummary": 20 * time.Millisecond},
+			CacheServeStale: serveStale,
+			Config: &Config{
+				BaseURL:   server.URL,
+				Endpoints: DefaultEndpoints(),
+			},
+		})
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+
+	ctx := context.Background()
+
+	t.Run("fresh entries are served from cache", func(t *testing.T) {
+		summaryCalls.Store(0)
+		failing.Store(false)
+		client := newClient(false)
+
+		for i := 0; i < 3; i++ {
+			summary, err := client.MarketSummary(ctx)
+			if err != nil {
+				t.Fatalf("MarketSummary failed: %v", err)
+			}
+			if summary.TotalTurnover != 1000 {
+				t.Errorf("expected turnover 1000, got %.0f", summary.TotalTurnover)
+			}
+		}
+		if summaryCalls.Load() != 1 {
+			t.Errorf("expected 1 upstream call, got %d", summaryCalls.Load())
+		}
+
+		// Endpoints without a TTL are never cached.
+		for i := 0; i < 2; i++ {
+			if _, err := client.MarketStatus(ctx); err != nil {
+				t.Fatalf("MarketStatus failed: %v", err)
+			}
+		}
+	})
+
+	t.Run("stale entry served on 5xx", func(t *testing.T) {
+		summaryCalls.Store(0)
+		failing.Store(false)
+		client := newClient(true)
+
+		if _, err := client.MarketSummary(ctx); err != nil {
+			t.Fatalf("MarketSummary failed: %v", err)
+		}
+		time.Sleep(30 * time.Millisecond)
+		failing.Store(true)
+
+		summary, err := client.MarketSummary(ctx)
+		if err != nil {
+			t.Fatalf("expected stale response, got error: %v", err)
+		}
+		if summary.TotalTurnover != 1000 {
+			t.Errorf("expected stale turnover 1000, got %.0f", summary.TotalTurnover)
+		}
+		if summaryCalls.Load() != 2 {
+			t.Errorf("expected expired entry to trigger a fetch, got %d calls", summaryCalls.Load())
+		}
+	})
+
+	t.Run("stale entry not served when disabled", func(t *testing.T) {
+		summaryCalls.Store(0)
+		failing.Store(false)
+		client := newClient(false)
+
+		if _, err := client.MarketSummary(ctx); err != nil {
+			t.Fatalf("MarketSummary failed: %v", err)
+		}
+		time.Sleep(30 * time.Millisecond)
+		failing.Store(true)
+
+		if _, err := client.MarketSummary(ctx); !errors.Is(err, ErrInvalidServerResponse) {
+			t.Errorf("expected ErrInvalidServerResponse, got %v", err)
+		}
+	})
+}
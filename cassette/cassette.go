@@ -0,0 +1,330 @@
+// Package cassette records NEPSE HTTP traffic to disk and replays it, so
+// tests can exercise the full client API offline and deterministically.
+//
+// Record once against the live API:
+//
+//	rec, err := cassette.NewRecorder("testdata/nepse", insecureTransport)
+//	opts.HTTPClient = &http.Client{Transport: rec}
+//
+// Then replay in CI:
+//
+//	rep, err := cassette.NewReplayer("testdata/nepse")
+//	opts.HTTPClient = &http.Client{Transport: rep}
+//
+// Requests are matched on method, path, query, and body. Values derived from
+// the access token are normalised so recordings stay valid: Authorization
+// headers are never stored, POST payload IDs on graph and security-detail
+// endpoints (which depend on token salts and the date) are ignored when
+// matching, and replayed token responses carry the
+// current server time so the client's token cache behaves as it would live.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenPath is the NEPSE endpoint that issues access tokens.
+const tokenPath = "/api/authenticate/prove"
+
+// payloadIDPaths are the path prefixes whose POST body "id" is a payload ID
+// rather than part of the query.
+var payloadIDPaths = []string{
+	"/api/nots/graph/",
+	"/api/nots/market/graphdata/",
+	"/api/nots/security/",
+}
+
+// Interaction is one recorded request/response pair, stored as a JSON file.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the matchable part of a recorded request.
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"` // Canonical encoding with sorted keys
+	Body   string `json:"body,omitempty"`  // Normalised body
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// key returns the string requests are matched on.
+func (r Request) key() string {
+	return r.Method + " " + r.Path + "?" + r.Query + " " + r.Body
+}
+
+// newRequest captures req in its normalised, matchable form.
+func newRequest(req *http.Request, body []byte) Request {
+	return Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Body:   normalizeBody(req.Method, req.URL.Path, body),
+	}
+}
+
+// normalizeBody canonicalises JSON bodies and blanks out POST payload IDs,
+// which NEPSE derives from token salts and the current date. Numbers are kept
+// exactly as sent.
+func normalizeBody(method, path string, body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil || dec.Decode(new(any)) != io.EOF {
+		return string(body)
+	}
+	if obj, ok := v.(map[string]any); ok && method == http.MethodPost && takesPayloadID(path) {
+		if _, ok := obj["id"]; ok {
+			obj["id"] = "*"
+		}
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(out)
+}
+
+// takesPayloadID reports whether POSTs to path carry a payload ID.
+func takesPayloadID(path string) bool {
+	for _, prefix := range payloadIDPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Recorder is an [http.RoundTripper] that forwards requests to the next
+// transport and writes every request/response pair to a directory.
+type Recorder struct {
+	dir  string
+	next http.RoundTripper
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewRecorder returns a Recorder that saves interactions to dir, creating it
+// if needed. If next is nil, [http.DefaultTransport] is used. Existing
+// recordings in dir are kept; new ones are numbered after them.
+func NewRecorder(dir string, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cassette: create dir: %w", err)
+	}
+	files, err := interactionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir, next: next, seq: lastSeq(files)}, nil
+}
+
+// lastSeq returns the highest sequence number among recorded files, or zero.
+func lastSeq(files []string) int {
+	last := 0
+	for _, f := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(f), "_")
+		if n, err := strconv.Atoi(prefix); err == nil && n > last {
+			last = n
+		}
+	}
+	return last
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cassette: read request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	in := Interaction{
+		Request:  newRequest(req, reqBody),
+		Response: Response{Status: resp.StatusCode, Header: header, Body: string(respBody)},
+	}
+	if err := r.save(in); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save(in Interaction) error {
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cassette: encode interaction: %w", err)
+	}
+
+	r.mu.Lock()
+	r.seq++
+	name := fmt.Sprintf("%04d_%s_%s.json", r.seq, in.Request.Method, slug(in.Request.Path))
+	r.mu.Unlock()
+
+	if err := os.WriteFile(filepath.Join(r.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("cassette: write interaction: %w", err)
+	}
+	return nil
+}
+
+// slug turns a URL path into a file-name-safe fragment.
+func slug(path string) string {
+	s := strings.Trim(path, "/")
+	s = strings.NewReplacer("/", "_", ".", "_").Replace(s)
+	if len(s) > 80 {
+		s = s[:80]
+	}
+	return s
+}
+
+// Replayer is an [http.RoundTripper] that serves recorded interactions
+// without network access. Identical requests are answered in recorded order;
+// once a request's recordings are used up, the last one is repeated.
+// Requests with no recording fail with a transport error.
+type Replayer struct {
+	mu     sync.Mutex
+	byKey  map[string][]Interaction
+	served map[string]int
+}
+
+// NewReplayer loads every interaction recorded in dir.
+func NewReplayer(dir string) (*Replayer, error) {
+	files, err := interactionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replayer{
+		byKey:  make(map[string][]Interaction),
+		served: make(map[string]int),
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: read %s: %w", f, err)
+		}
+		var in Interaction
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("cassette: decode %s: %w", f, err)
+		}
+		k := in.Request.key()
+		r.byKey[k] = append(r.byKey[k], in)
+	}
+	return r, nil
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cassette: read request body: %w", err)
+		}
+	}
+	key := newRequest(req, body).key()
+
+	r.mu.Lock()
+	recorded := r.byKey[key]
+	if len(recorded) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("cassette: no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+	}
+	i := min(r.served[key], len(recorded)-1)
+	r.served[key]++
+	in := recorded[i]
+	r.mu.Unlock()
+
+	respBody := []byte(in.Response.Body)
+	if req.URL.Path == tokenPath {
+		respBody = refreshServerTime(respBody)
+	}
+
+	header := in.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+		StatusCode:    in.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// refreshServerTime stamps a recorded token response with the current time so
+// replayed tokens are treated as fresh.
+func refreshServerTime(body []byte) []byte {
+	var token map[string]any
+	if err := json.Unmarshal(body, &token); err != nil {
+		return body
+	}
+	if _, ok := token["serverTime"]; !ok {
+		return body
+	}
+	token["serverTime"] = time.Now().UnixMilli()
+	out, err := json.Marshal(token)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// interactionFiles lists recorded interaction files in dir in recording order.
+func interactionFiles(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("cassette: list %s: %w", dir, err)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
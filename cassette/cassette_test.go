@@ -0,0 +1,221 @@
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+const recordedToken = `{"salt1":1234,"salt2":5678,"salt3":9012,"salt4":3456,"salt5":7890,` +
+	`"accessToken":"testXtokenYwithZjunkAcharsB","refreshToken":"refreshXtokenY","serverTime":1000}`
+
+func newTestClient(t *testing.T, baseURL string, rt http.RoundTripper) *nepse.Client {
+	t.Helper()
+	client, err := nepse.NewClient(&nepse.Options{
+		BaseURL:     baseURL,
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  0,
+		HTTPClient:  &http.Client{Transport: rt},
+		Config: &nepse.Config{
+			BaseURL:   baseURL,
+			Endpoints: nepse.DefaultEndpoints(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	var postBodies []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			w.Write([]byte(recordedToken))
+		case "/api/nots/nepse-data/market-open":
+			w.Write([]byte(`{"isOpen":"OPEN","asOf":"2026-01-02T11:00:00","id":61}`))
+		case "/api/nots/market/graphdata/daily/131":
+			b, _ := io.ReadAll(r.Body)
+			postBodies = append(postBodies, string(b))
+			w.Write([]byte(`[[1767330000,1250.5],[1767330060,1251]]`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	dir := t.TempDir()
+
+	rec, err := NewRecorder(dir, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	live := newTestClient(t, server.URL, rec)
+
+	ctx := context.Background()
+	wantGraph, err := live.DailyScripGraph(ctx, 131)
+	if err != nil {
+		t.Fatalf("recording DailyScripGraph failed: %v", err)
+	}
+	server.Close()
+
+	if len(postBodies) != 1 || !strings.Contains(postBodies[0], `"id":`) {
+		t.Fatalf("expected one POST with a payload id, got %v", postBodies)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) == 0 {
+		t.Fatal("expected recorded interaction files")
+	}
+	for _, f := range files {
+		data, _ := os.ReadFile(dir + "/" + f.Name())
+		if strings.Contains(string(data), "Salter") {
+			t.Errorf("%s contains an Authorization header", f.Name())
+		}
+	}
+
+	rep, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	offline := newTestClient(t, server.URL, rep)
+
+	gotGraph, err := offline.DailyScripGraph(ctx, 131)
+	if err != nil {
+		t.Fatalf("replayed DailyScripGraph failed: %v", err)
+	}
+	if len(gotGraph.Data) != len(wantGraph.Data) || gotGraph.Data[0] != wantGraph.Data[0] {
+		t.Errorf("replayed graph = %+v, want %+v", gotGraph.Data, wantGraph.Data)
+	}
+
+	if _, err := offline.Token(ctx); err != nil {
+		t.Errorf("replayed Token failed: %v", err)
+	}
+}
+
+func TestReplayer_Unmatched(t *testing.T) {
+	rep, err := NewReplayer(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	client := newTestClient(t, "https://nepse.invalid", rep)
+
+	_, err = client.Token(context.Background())
+	if !errors.Is(err, nepse.ErrNetworkError) {
+		t.Errorf("expected ErrNetworkError for unrecorded request, got %v", err)
+	}
+}
+
+func TestReplayer_RepeatsInOrder(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	dir := t.TempDir()
+	rec, err := NewRecorder(dir, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	hc := &http.Client{Transport: rec}
+	for range 2 {
+		resp, err := hc.Get(server.URL + "/api/x?b=2&a=1")
+		if err != nil {
+			t.Fatalf("recording GET failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+	server.Close()
+
+	rep, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	hc = &http.Client{Transport: rep}
+	for i, want := range []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK} {
+		resp, err := hc.Get(server.URL + "/api/x?a=1&b=2")
+		if err != nil {
+			t.Fatalf("replay %d failed: %v", i, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("replay %d status = %d, want %d", i, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestNormalizeBody(t *testing.T) {
+	const graph = "/api/nots/graph/index/58"
+	tests := []struct {
+		method, path, body, want string
+	}{
+		{http.MethodPost, graph, `{"id": 12345}`, `{"id":"*"}`},
+		{http.MethodPost, "/api/nots/security/131", `{"id":1,"b":2,"a":1}`, `{"a":1,"b":2,"id":"*"}`},
+		{http.MethodPost, "/api/nots/other", `{"id":7}`, `{"id":7}`},
+		{http.MethodGet, graph, `{"id":7}`, `{"id":7}`},
+		{http.MethodPost, "/api/nots/other", `{"id":12345678901234567890,"v":1.50}`, `{"id":12345678901234567890,"v":1.50}`},
+		{http.MethodPost, graph, `{"id":1} {}`, `{"id":1} {}`},
+		{http.MethodPost, graph, `not json`, `not json`},
+		{http.MethodPost, graph, "  ", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeBody(tt.method, tt.path, []byte(tt.body)); got != tt.want {
+			t.Errorf("normalizeBody(%s, %s, %q) = %q, want %q", tt.method, tt.path, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestRefreshServerTime(t *testing.T) {
+	before := time.Now().UnixMilli()
+	var token struct {
+		ServerTime  int64  `json:"serverTime"`
+		AccessToken string `json:"accessToken"`
+	}
+	if err := json.Unmarshal(refreshServerTime([]byte(recordedToken)), &token); err != nil {
+		t.Fatalf("refreshed token is not valid JSON: %v", err)
+	}
+	if token.ServerTime < before {
+		t.Errorf("serverTime = %d, want >= %d", token.ServerTime, before)
+	}
+	if token.AccessToken != "testXtokenYwithZjunkAcharsB" {
+		t.Errorf("accessToken changed to %q", token.AccessToken)
+	}
+}
+
+func TestRecorder_ContinuesAfterHighestSequence(t *testing.T) {
+	dir := t.TempDir()
+	// A gap left by a deleted recording must not cause the next one to
+	// overwrite 0003.
+	for _, name := range []string{"0001_GET_a.json", "0003_GET_b.json", "notes.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec, err := NewRecorder(dir, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	if err := rec.save(Interaction{Request: Request{Method: http.MethodGet, Path: "/api/nots"}}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "0004_GET_api_nots.json")); err != nil {
+		t.Errorf("expected the next recording to be 0004: %v", err)
+	}
+}
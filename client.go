@@ -1,17 +1,27 @@
 package nepse
 
 import (
+	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/voidarchive/go-nepse/internal/auth"
+	"github.com/voidarchive/go-nepse/payload"
 )
 
 // Client is the NEPSE API client. Use [NewClient] to create one.
 type Client struct {
 	httpClient  *http.Client
+	doer        Doer // httpClient wrapped in Options.Middleware
 	config      *Config
 	authManager *auth.Manager
+	payload     *payload.Table
+	payloadIDs  payloadCache
+	registry    *SecurityRegistry
+	limiter     *rateLimiter
+	retryPolicy RetryPolicy
+	logger      *slog.Logger
 	options     *Options
 }
 
@@ -21,11 +31,72 @@ type Options struct {
 	TLSVerification bool          // Set false only for development; NEPSE uses self-signed certs
 	HTTPTimeout     time.Duration // Per-request timeout
 	MaxRetries      int           // Retry count for transient failures (5xx, rate limits)
-	RetryDelay      time.Duration // Base delay; actual delay uses exponential backoff
+	RetryDelay      time.Duration // Base delay; actual delay uses exponential backoff with full jitter
+	RetryPolicy     RetryPolicy   // Custom retry decisions; nil uses NewExponentialBackoff(MaxRetries, RetryDelay)
+	RateLimit       float64       // Max requests per second across all goroutines; zero disables limiting
+	RateBurst       int           // Requests allowed at once before RateLimit applies; zero means 1
 	Config          *Config       // API endpoint paths and headers
 	HTTPClient      *http.Client  // Bring your own client; nil uses sensible defaults
+	Middleware      []Middleware  // Wraps every HTTP attempt, including token requests; first is outermost
+	RegistryTTL     time.Duration // How long cached security/company lists stay fresh; zero uses DefaultRegistryTTL
+	Logger          *slog.Logger  // Structured request and auth events; nil discards them. Tokens are always redacted
+	TokenParser     TokenParser   // How access tokens are decoded; zero uses the embedded WASM module
+	Clock           Clock         // Time source for token expiry and graph payload IDs; nil uses the system clock
+
+	// Token refresh. Tokens are fetched lazily on first use and after expiry
+	// unless BackgroundRefresh is set.
+	BackgroundRefresh bool                    // Renew the token in the background shortly before it expires; stopped by Close
+	OnTokenRefresh    func(TokenRefreshEvent) // Called after every refresh attempt, lazy or background; must return quickly
+
+	// Response caching. Only endpoints listed in CacheTTL are cached.
+	Cache           Cache                    // Response store, e.g. NewMemoryCache or NewDiskCache; nil disables caching
+	CacheTTL        map[string]time.Duration // Per-endpoint TTL keyed by Endpoints field name, e.g. "MarketSummary"
+	CacheServeStale bool                     // Serve expired entries when NEPSE returns 5xx or times out
+
+	// Auth artifacts, for when NEPSE rotates its obfuscation before a library
+	// release catches up. NewClient checks them against test vectors and fails
+	// if any vector does not hold.
+	WASMModule       io.Reader       // Replacement css.wasm; nil falls back to WASMModulePath, then the embedded module
+	WASMModulePath   string          // File holding a replacement css.wasm
+	PayloadTable     io.Reader       // Replacement dummyData as a JSON array of 100 ints; nil falls back to PayloadTablePath, then the built-in table
+	PayloadTablePath string          // File holding a replacement payload table
+	TokenVectors     []TokenVector   // Known salts and token indices; required with a replacement module, nil otherwise uses vectors for the embedded css.wasm
+	PayloadVectors   []PayloadVector // Known base payload IDs; required with a replacement table, nil otherwise uses vectors for the built-in table
+}
+
+// TokenVector is a set of salts from /api/authenticate/prove and the character
+// positions css.wasm strips from the access and refresh tokens for them.
+type TokenVector = auth.TokenVector
+
+// PayloadVector is a market status ID and day of month with the base payload
+// ID NEPSE's website computes for them.
+type PayloadVector = payload.Vector
+
+// Clock tells the time. Set Options.Clock to pin time in tests. The client
+// corrects it by the offset between it and NEPSE's serverTime, measured at
+// every token refresh.
+type Clock interface {
+	Now() time.Time
 }
 
+// TokenRefreshEvent describes one token refresh attempt.
+type TokenRefreshEvent = auth.RefreshEvent
+
+// TokenStats counts token refreshes and reports the current token's age.
+type TokenStats = auth.RefreshStats
+
+// TokenParser selects how the client decodes NEPSE's obfuscated access tokens.
+type TokenParser int
+
+const (
+	// TokenParserWASM runs NEPSE's css.wasm under the wazero runtime. It is the
+	// reference implementation and the default.
+	TokenParserWASM TokenParser = iota
+	// TokenParserNative uses a pure-Go port of css.wasm. It avoids the WASM
+	// runtime's memory and startup cost, which matters for short-lived jobs.
+	TokenParserNative
+)
+
 // DefaultOptions returns sensible defaults for the NEPSE client.
 func DefaultOptions() *Options {
 	return &Options{
@@ -35,6 +106,7 @@ func DefaultOptions() *Options {
 		MaxRetries:      3,
 		RetryDelay:      time.Second,
 		Config:          DefaultConfig(),
+		RegistryTTL:     DefaultRegistryTTL,
 	}
 }
 
@@ -55,7 +127,27 @@ func (c *Client) Config() *Config {
 	return c.config
 }
 
-// Close releases resources held by the client.
+// TokenStats returns token refresh counters and the current token's age.
+func (c *Client) TokenStats() TokenStats {
+	return c.authManager.Stats()
+}
+
+// now reads Options.Clock, falling back to the system clock.
+func (c *Client) now() time.Time {
+	if c.options.Clock != nil {
+		return c.options.Clock.Now()
+	}
+	return time.Now()
+}
+
+// ServerTime estimates NEPSE's current time from the client's clock and the
+// offset measured at the last token refresh.
+func (c *Client) ServerTime() time.Time {
+	return c.authManager.ServerNow()
+}
+
+// Close releases resources held by the client and stops the background
+// token refresher.
 func (c *Client) Close() error {
 	if c.authManager != nil {
 		return c.authManager.Close()
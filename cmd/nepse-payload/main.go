@@ -0,0 +1,121 @@
+// Command nepse-payload prints the POST payload IDs NEPSE expects for a market
+// status ID, day, and token salts, for checking other implementations of the
+// algorithm against this one.
+//
+// Usage:
+//
+//	nepse-payload -status 61 [-day 1] [-salts 1234,5678,9012,3456,7890] [-table dummyData.json] [-json]
+//
+// The day defaults to today in Nepal Time. The index graph ID is printed only
+// when salts are given.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "nepse-payload:", err)
+		os.Exit(2)
+	}
+}
+
+// result is the -json output.
+type result struct {
+	StatusID   int     `json:"statusId"`
+	Day        int     `json:"day"`
+	Salts      *[5]int `json:"salts,omitempty"`
+	Base       int     `json:"base"`
+	ScripGraph int     `json:"scripGraph"`
+	IndexGraph *int    `json:"indexGraph,omitempty"`
+}
+
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("nepse-payload", flag.ContinueOnError)
+	status := fs.Int("status", -1, "market status ID from /api/nots/nepse-data/market-open (required)")
+	day := fs.Int("day", payload.Day(time.Now()), "day of month in Nepal Time")
+	saltList := fs.String("salts", "", "comma-separated salt1..salt5 from the token response")
+	tablePath := fs.String("table", "", "JSON file with a replacement 100-entry dummyData table")
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *status < 0 {
+		return errors.New("-status is required")
+	}
+	if *day < 1 || *day > 31 {
+		return fmt.Errorf("-day %d is not a day of month", *day)
+	}
+
+	table := payload.DefaultTable()
+	if *tablePath != "" {
+		f, err := os.Open(*tablePath)
+		if err != nil {
+			return err
+		}
+		table, err = payload.ParseTable(f)
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	r := result{
+		StatusID:   *status,
+		Day:        *day,
+		Base:       table.Base(*status, *day),
+		ScripGraph: table.ScripGraph(*status, *day),
+	}
+	if *saltList != "" {
+		s, err := parseSalts(*saltList)
+		if err != nil {
+			return err
+		}
+		id := table.IndexGraph(*status, *day, payload.Salts{
+			Salt1: s[0], Salt2: s[1], Salt3: s[2], Salt4: s[3], Salt5: s[4],
+		})
+		r.Salts, r.IndexGraph = &s, &id
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	fmt.Fprintf(stdout, "status      %d\n", r.StatusID)
+	fmt.Fprintf(stdout, "day         %d\n", r.Day)
+	fmt.Fprintf(stdout, "base        %d\n", r.Base)
+	fmt.Fprintf(stdout, "scripGraph  %d\n", r.ScripGraph)
+	if r.IndexGraph != nil {
+		fmt.Fprintf(stdout, "indexGraph  %d\n", *r.IndexGraph)
+	}
+	return nil
+}
+
+// parseSalts parses exactly five comma-separated integers.
+func parseSalts(list string) ([5]int, error) {
+	var salts [5]int
+	fields := strings.Split(list, ",")
+	if len(fields) != len(salts) {
+		return salts, fmt.Errorf("-salts needs 5 values, got %d", len(fields))
+	}
+	for i, f := range fields {
+		v, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return salts, fmt.Errorf("-salts: %w", err)
+		}
+		salts[i] = v
+	}
+	return salts, nil
+}
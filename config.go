@@ -1,5 +1,10 @@
 package nepse
 
+import (
+	"reflect"
+	"strings"
+)
+
 // DefaultBaseURL is the production NEPSE API URL.
 const DefaultBaseURL = "https://nepalstock.com.np"
 
@@ -134,3 +139,29 @@ func DefaultConfig() *Config {
 		Endpoints: DefaultEndpoints(),
 	}
 }
+
+// fieldFor returns the name of the Endpoints field that serves endpoint, such
+// as "MarketDepth" for "/api/nots/nepse-data/marketdepth/131". Query strings
+// and a trailing ID segment are ignored when there is no exact match.
+// Returns "" for paths not covered by Endpoints.
+func (e *Endpoints) fieldFor(endpoint string) string {
+	candidates := []string{endpoint}
+	path, _, hasQuery := strings.Cut(endpoint, "?")
+	if hasQuery {
+		candidates = append(candidates, path)
+	}
+	if i := strings.LastIndexByte(path, '/'); i > 0 {
+		candidates = append(candidates, path[:i])
+	}
+
+	v := reflect.ValueOf(e).Elem()
+	t := v.Type()
+	for _, candidate := range candidates {
+		for i := 0; i < t.NumField(); i++ {
+			if v.Field(i).String() == candidate {
+				return t.Field(i).Name
+			}
+		}
+	}
+	return ""
+}
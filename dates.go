@@ -0,0 +1,253 @@
+package nepse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+// NPT is Nepal Time, the zone every [Date] and [DateTime] is pinned to:
+// Asia/Kathmandu from the tz database, with a fixed +05:45 fallback when
+// tzdata is missing. It is the same location as [payload.NPT], so dates here
+// and payload day numbers always agree.
+var NPT = payload.NPT
+
+// Layouts NEPSE timestamps without a zone are parsed with, in NPT. The two
+// layouts with a seconds field also accept a fractional second after it.
+var dateTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	DateTimeFormat,
+	"2006-01-02T15:04",
+	DateFormat,
+}
+
+// Date is a calendar date in Nepal Time, such as a business date.
+//
+// It decodes from "2006-01-02" and from any form [DateTime] accepts, taking
+// the date in NPT, and encodes as "2006-01-02". JSON null and "" decode to
+// the zero Date, which encodes as null.
+type Date struct {
+	t time.Time // midnight NPT
+}
+
+// NewDate returns the given date.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, NPT)}
+}
+
+// DateOf returns the date in Nepal at t.
+func DateOf(t time.Time) Date {
+	return NewDate(t.In(NPT).Date())
+}
+
+// ParseDate parses a date in any form Date decodes from.
+func ParseDate(s string) (Date, error) {
+	if t, err := time.ParseInLocation(DateFormat, s, NPT); err == nil {
+		return Date{t}, nil
+	}
+	dt, err := ParseDateTime(s)
+	if err != nil {
+		return Date{}, fmt.Errorf("parse date %q: not a NEPSE date", s)
+	}
+	return DateOf(dt.t), nil
+}
+
+// Time returns midnight NPT at the start of d.
+func (d Date) Time() time.Time { return d.t }
+
+// IsZero reports whether d is the zero Date.
+func (d Date) IsZero() bool { return d.t.IsZero() }
+
+// AddDays returns d moved by n days.
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.t.Year(), d.t.Month(), d.t.Day()+n)
+}
+
+// Compare returns -1, 0, or +1 as d is before, equal to, or after u.
+func (d Date) Compare(u Date) int { return d.t.Compare(u.t) }
+
+// Before reports whether d is before u.
+func (d Date) Before(u Date) bool { return d.t.Before(u.t) }
+
+// After reports whether d is after u.
+func (d Date) After(u Date) bool { return d.t.After(u.t) }
+
+// String returns d as "2006-01-02", or "" for the zero Date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.t.Format(DateFormat)
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var dt DateTime
+	if err := dt.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	if dt.IsZero() {
+		*d = Date{}
+		return nil
+	}
+	*d = DateOf(dt.t)
+	return nil
+}
+
+// DateTime is an instant reported by NEPSE, in Nepal Time.
+//
+// It decodes from the string forms NEPSE uses: "2006-01-02T15:04:05" and
+// "2006-01-02 15:04:05", each with an optional fractional second and read as
+// NPT; RFC 3339 with a zone; a bare date; and epoch seconds or milliseconds
+// as a JSON number, as in graph data. It encodes as
+// "2006-01-02T15:04:05.999" in NPT. JSON null and "" decode to the zero
+// DateTime, which encodes as null. Text encoding uses the same forms, with
+// "" for the zero DateTime.
+type DateTime struct {
+	t time.Time
+}
+
+// dateTimeLayout is the layout DateTime encodes with.
+const dateTimeLayout = "2006-01-02T15:04:05.999"
+
+// DateTimeOf returns t as a DateTime in NPT.
+func DateTimeOf(t time.Time) DateTime {
+	return DateTime{t.In(NPT)}
+}
+
+// ParseDateTime parses a timestamp in any string form DateTime decodes from.
+func ParseDateTime(s string) (DateTime, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return DateTimeOf(t), nil
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, NPT); err == nil {
+			return DateTime{t}, nil
+		}
+	}
+	return DateTime{}, fmt.Errorf("parse date-time %q: not a NEPSE timestamp", s)
+}
+
+// epochMillisThreshold separates epoch seconds from milliseconds: a value
+// this large in seconds would be thousands of years away.
+const epochMillisThreshold = 1e11
+
+// dateTimeFromEpoch reads v as epoch seconds, or milliseconds when large.
+func dateTimeFromEpoch(v float64) DateTime {
+	if math.Abs(v) >= epochMillisThreshold {
+		return DateTimeOf(time.UnixMilli(int64(v)))
+	}
+	sec, frac := math.Modf(v)
+	return DateTimeOf(time.Unix(int64(sec), int64(frac*1e9)))
+}
+
+// Time returns t as a [time.Time] in NPT.
+func (t DateTime) Time() time.Time { return t.t }
+
+// IsZero reports whether t is the zero DateTime.
+func (t DateTime) IsZero() bool { return t.t.IsZero() }
+
+// Date returns the date in Nepal at t.
+func (t DateTime) Date() Date {
+	return DateOf(t.t)
+}
+
+// Compare returns -1, 0, or +1 as t is before, equal to, or after u.
+func (t DateTime) Compare(u DateTime) int { return t.t.Compare(u.t) }
+
+// Before reports whether t is before u.
+func (t DateTime) Before(u DateTime) bool { return t.t.Before(u.t) }
+
+// After reports whether t is after u.
+func (t DateTime) After(u DateTime) bool { return t.t.After(u.t) }
+
+// Equal reports whether t and u are the same instant.
+func (t DateTime) Equal(u DateTime) bool { return t.t.Equal(u.t) }
+
+// String returns t as "2006-01-02 15:04:05" in NPT, or "" for the zero DateTime.
+func (t DateTime) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.t.In(NPT).Format(DateTimeFormat)
+}
+
+// MarshalText implements [encoding.TextMarshaler], writing the same form as
+// [DateTime.MarshalJSON], or "" for the zero DateTime.
+func (t DateTime) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+	return []byte(t.t.In(NPT).Format(dateTimeLayout)), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], accepting the string
+// forms [DateTime.UnmarshalJSON] accepts. "" decodes to the zero DateTime.
+func (t *DateTime) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*t = DateTime{}
+		return nil
+	}
+	parsed, err := ParseDateTime(string(data))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (t DateTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.t.In(NPT).Format(dateTimeLayout))
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (t *DateTime) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*t = DateTime{}
+		return nil
+	}
+	if len(data) > 0 && data[0] != '"' {
+		v, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return fmt.Errorf("parse date-time %s: not a NEPSE timestamp", data)
+		}
+		*t = dateTimeFromEpoch(v)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return t.UnmarshalText([]byte(s))
+}
+
+// DateRange is an inclusive range of dates. A zero From or To leaves that
+// end of the range to NEPSE.
+type DateRange struct {
+	From Date
+	To   Date
+}
+
+// Bounded reports whether both ends of r are set.
+func (r DateRange) Bounded() bool {
+	return !r.From.IsZero() && !r.To.IsZero()
+}
@@ -0,0 +1,187 @@
+package nepse
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDateTime_UnmarshalJSON(t *testing.T) {
+	at := func(hour, minute, sec, nsec int) time.Time {
+		return time.Date(2026, 1, 2, hour, minute, sec, nsec, NPT)
+	}
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{"zoneless T", `"2026-01-02T15:00:00"`, at(15, 0, 0, 0)},
+		{"zoneless T with fraction", `"2026-01-02T15:00:00.123"`, at(15, 0, 0, 123_000_000)},
+		{"zoneless space", `"2026-01-02 15:00:00"`, at(15, 0, 0, 0)},
+		{"zoneless space with fraction", `"2026-01-02 15:00:00.5"`, at(15, 0, 0, 500_000_000)},
+		{"minutes only", `"2026-01-02T15:04"`, at(15, 4, 0, 0)},
+		{"date only", `"2026-01-02"`, at(0, 0, 0, 0)},
+		{"RFC 3339 UTC", `"2026-01-02T09:15:00Z"`, at(15, 0, 0, 0)},
+		{"RFC 3339 offset", `"2026-01-02T15:00:00+05:45"`, at(15, 0, 0, 0)},
+		{"epoch seconds", `1767345300`, at(15, 0, 0, 0)},
+		{"epoch milliseconds", `1767345300000`, at(15, 0, 0, 0)},
+		{"epoch fractional seconds", `1767345300.25`, at(15, 0, 0, 250_000_000)},
+		{"null", `null`, time.Time{}},
+		{"empty", `""`, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got DateTime
+			if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.in, err)
+			}
+			if !got.Time().Equal(tt.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got.Time(), tt.want)
+			}
+			if !got.IsZero() && got.Time().Location() != NPT {
+				t.Errorf("Unmarshal(%s) location = %v, want NPT", tt.in, got.Time().Location())
+			}
+		})
+	}
+
+	for _, in := range []string{`"yesterday"`, `"2026-13-01"`, `true`, `{}`} {
+		var got DateTime
+		if err := json.Unmarshal([]byte(in), &got); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestDate_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Date
+	}{
+		{`"2026-01-02"`, NewDate(2026, 1, 2)},
+		{`"2026-01-02T23:59:59.9"`, NewDate(2026, 1, 2)},
+		{`"2026-01-01T18:30:00Z"`, NewDate(2026, 1, 2)}, // 00:15 NPT
+		{`1767292200000`, NewDate(2026, 1, 2)},
+		{`null`, Date{}},
+		{`""`, Date{}},
+	}
+	for _, tt := range tests {
+		var got Date
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDates_RoundTrip(t *testing.T) {
+	type row struct {
+		Day   Date     `json:"businessDate"`
+		At    DateTime `json:"tradeTime"`
+		Unset DateTime `json:"lastUpdatedDateTime"`
+	}
+	in := row{
+		Day: NewDate(2026, 1, 2),
+		At:  DateTimeOf(time.Date(2026, 1, 2, 9, 15, 0, 123_000_000, time.UTC)),
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"businessDate":"2026-01-02","tradeTime":"2026-01-02T15:00:00.123","lastUpdatedDateTime":null}`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	var out row
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Day != in.Day || !out.At.Equal(in.At) || !out.Unset.IsZero() {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestDateTime_TextMatchesJSON(t *testing.T) {
+	at := DateTimeOf(time.Date(2026, 1, 2, 9, 15, 0, 123_000_000, time.UTC))
+	text, err := at.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	js, err := json.Marshal(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := strconv.Unquote(string(js)); string(text) != want {
+		t.Errorf("MarshalText = %q, MarshalJSON = %s", text, js)
+	}
+
+	// Map keys encode through MarshalText.
+	keyed, err := json.Marshal(map[DateTime]int{at: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"2026-01-02T15:00:00.123":1}`; string(keyed) != want {
+		t.Errorf("map key = %s, want %s", keyed, want)
+	}
+
+	// Zoneless NEPSE forms decode as text too.
+	for _, in := range []string{"2026-01-02 15:00:00.123", "2026-01-02T15:00:00.123", string(text)} {
+		var got DateTime
+		if err := got.UnmarshalText([]byte(in)); err != nil {
+			t.Fatalf("UnmarshalText(%q): %v", in, err)
+		}
+		if !got.Equal(at) {
+			t.Errorf("UnmarshalText(%q) = %v, want %v", in, got, at)
+		}
+	}
+
+	var zero DateTime
+	if text, _ := zero.MarshalText(); len(text) != 0 {
+		t.Errorf("zero MarshalText = %q, want empty", text)
+	}
+	if err := zero.UnmarshalText(nil); err != nil || !zero.IsZero() {
+		t.Errorf("UnmarshalText(\"\") = %v, %v; want zero", zero, err)
+	}
+}
+
+func TestDate_Methods(t *testing.T) {
+	d := NewDate(2024, 2, 28)
+	if got := d.AddDays(2); got != NewDate(2024, 3, 1) {
+		t.Errorf("AddDays(2) = %v, want 2024-03-01", got)
+	}
+	if !d.Before(d.AddDays(1)) || !d.AddDays(1).After(d) || d.Compare(d) != 0 {
+		t.Error("Before, After, or Compare disagree with AddDays")
+	}
+	if s := (Date{}).String(); s != "" {
+		t.Errorf("zero Date String = %q, want empty", s)
+	}
+	if s := d.String(); s != "2024-02-28" {
+		t.Errorf("String = %q, want 2024-02-28", s)
+	}
+
+	// 20:00 UTC is already the next day in Nepal.
+	at := DateTimeOf(time.Date(2024, 2, 28, 20, 0, 0, 0, time.UTC))
+	if got := at.Date(); got != NewDate(2024, 2, 29) {
+		t.Errorf("Date() = %v, want 2024-02-29", got)
+	}
+	if s := at.String(); s != "2024-02-29 01:45:00" {
+		t.Errorf("String = %q, want 2024-02-29 01:45:00", s)
+	}
+	// The underlying time keeps time.Time's own Date.
+	if y, m, day := at.Time().Date(); y != 2024 || m != time.February || day != 29 {
+		t.Errorf("Time().Date() = %d-%d-%d, want 2024-2-29", y, m, day)
+	}
+	if !at.Before(DateTimeOf(at.Time().Add(time.Second))) || at.Compare(at) != 0 {
+		t.Error("Before or Compare disagree with Time")
+	}
+}
+
+func TestNPT_Offset(t *testing.T) {
+	_, offset := time.Date(2026, 1, 2, 0, 0, 0, 0, NPT).Zone()
+	if offset != 5*3600+45*60 {
+		t.Errorf("NPT offset = %ds, want +05:45", offset)
+	}
+}
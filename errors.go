@@ -2,9 +2,16 @@ package nepse
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+// maxBodyExcerpt is how many bytes of an error response body NepseError keeps.
+const maxBodyExcerpt = 256
+
 // NepseError is the error type returned by all NEPSE API operations.
 // Use errors.Is with sentinel errors (e.g., ErrNotFound) to check error categories,
 // or errors.As to extract the full error details.
@@ -12,6 +19,15 @@ type NepseError struct {
 	Type    ErrorType // Category of error
 	Message string    // Human-readable description
 	Err     error     // Underlying error, if any
+
+	// Request details, set when the error comes from an HTTP exchange.
+	StatusCode int           // HTTP status code; 0 if no response was received
+	Method     string        // HTTP method
+	Endpoint   string        // Request path and query; never includes credentials
+	Attempts   int           // HTTP attempts made, including retries
+	Elapsed    time.Duration // Time from the first attempt to the final outcome
+	Body       string        // Start of the response body, at most 256 bytes
+	RetryAfter time.Duration // Parsed Retry-After header; 0 if absent
 }
 
 // ErrorType categorizes NEPSE errors for programmatic handling.
@@ -26,6 +42,9 @@ const (
 	ErrorTypeNotFound              ErrorType = "not_found"
 	ErrorTypeRateLimit             ErrorType = "rate_limit"
 	ErrorTypeInternal              ErrorType = "internal_error"
+	ErrorTypeEndpointBlocked       ErrorType = "endpoint_blocked"
+	ErrorTypeEmptyResponse         ErrorType = "empty_response"
+	ErrorTypeMarketClosed          ErrorType = "market_closed"
 )
 
 // Sentinel errors for use with [errors.Is].
@@ -39,17 +58,48 @@ var (
 	ErrNotFound              = &NepseError{Type: ErrorTypeNotFound}
 	ErrRateLimit             = &NepseError{Type: ErrorTypeRateLimit}
 	ErrInternal              = &NepseError{Type: ErrorTypeInternal}
+	ErrEndpointBlocked       = &NepseError{Type: ErrorTypeEndpointBlocked}
+	ErrEmptyResponse         = &NepseError{Type: ErrorTypeEmptyResponse}
+	ErrMarketClosed          = &NepseError{Type: ErrorTypeMarketClosed}
 )
 
 // Error implements the error interface.
+// Request details are included when present, for example:
+//
+//	nepse: resource not found (GET /api/nots/security/99999: status 404, 1 attempt, 85ms): "{\"message\":\"Not Found\"}"
 func (e *NepseError) Error() string {
-	if e.Message == "" && e.Err == nil {
-		return fmt.Sprintf("nepse: %s", e.Type)
+	var b strings.Builder
+	b.WriteString("nepse: ")
+	if e.Message != "" {
+		b.WriteString(e.Message)
+	} else {
+		b.WriteString(string(e.Type))
+	}
+
+	if e.Method != "" {
+		fmt.Fprintf(&b, " (%s %s:", e.Method, e.Endpoint)
+		if e.StatusCode != 0 {
+			fmt.Fprintf(&b, " status %d,", e.StatusCode)
+		}
+		if e.Attempts == 1 {
+			b.WriteString(" 1 attempt")
+		} else {
+			fmt.Fprintf(&b, " %d attempts", e.Attempts)
+		}
+		fmt.Fprintf(&b, ", %v", e.Elapsed.Round(time.Millisecond))
+		if e.RetryAfter > 0 {
+			fmt.Fprintf(&b, ", retry after %v", e.RetryAfter)
+		}
+		b.WriteString(")")
+	}
+
+	if e.Body != "" {
+		fmt.Fprintf(&b, ": %q", e.Body)
 	}
 	if e.Err != nil {
-		return fmt.Sprintf("nepse: %s: %v", e.Message, e.Err)
+		fmt.Fprintf(&b, ": %v", e.Err)
 	}
-	return fmt.Sprintf("nepse: %s", e.Message)
+	return b.String()
 }
 
 // Unwrap returns the underlying error.
@@ -124,6 +174,33 @@ func NewInternalError(message string, err error) *NepseError {
 	return NewNepseError(ErrorTypeInternal, message, err)
 }
 
+// NewEndpointBlockedError returns an error for an endpoint NEPSE refuses to serve
+// at all, such as the per-security floor sheet. It wraps cause, which carries the
+// request details, so errors.Is also matches cause's type.
+func NewEndpointBlockedError(endpoint string, cause *NepseError) *NepseError {
+	var err error
+	if cause != nil {
+		err = cause
+	}
+	return NewNepseError(ErrorTypeEndpointBlocked, endpoint+" endpoint is blocked by NEPSE", err)
+}
+
+// NewEmptyResponseError returns an error when NEPSE answers successfully but
+// with no data where some is always expected.
+func NewEmptyResponseError(what string) *NepseError {
+	return NewNepseError(ErrorTypeEmptyResponse, what+" response is empty", nil)
+}
+
+// NewMarketClosedError returns an error when data is unavailable because the
+// market is closed. asOf is the market status timestamp, if known.
+func NewMarketClosedError(asOf string) *NepseError {
+	message := "market is closed"
+	if asOf != "" {
+		message += " (as of " + asOf + ")"
+	}
+	return NewNepseError(ErrorTypeMarketClosed, message, nil)
+}
+
 // MapHTTPStatusToError converts an HTTP status code to the appropriate NepseError.
 func MapHTTPStatusToError(statusCode int, message string) *NepseError {
 	switch statusCode {
@@ -151,8 +228,48 @@ func MapHTTPStatusToError(statusCode int, message string) *NepseError {
 	}
 }
 
+// withRequest records the details of the HTTP exchange that produced e.
+// resp may be nil for transport failures. It consumes up to maxBodyExcerpt
+// bytes of the response body.
+func (e *NepseError) withRequest(req *http.Request, resp *http.Response, stats requestStats) *NepseError {
+	e.Method = req.Method
+	e.Endpoint = req.URL.Path
+	if req.URL.RawQuery != "" {
+		e.Endpoint += "?" + req.URL.RawQuery
+	}
+	e.Attempts = stats.attempts
+	e.Elapsed = stats.elapsed
+
+	if resp != nil {
+		e.StatusCode = resp.StatusCode
+		e.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		e.Body = bodyExcerpt(resp.Body)
+	}
+	return e
+}
+
+// bodyExcerpt reads the start of r for inclusion in an error message.
+func bodyExcerpt(r io.Reader) string {
+	buf, _ := io.ReadAll(io.LimitReader(r, maxBodyExcerpt+1))
+	truncated := len(buf) > maxBodyExcerpt
+	if truncated {
+		buf = buf[:maxBodyExcerpt]
+		// Don't split a multi-byte character.
+		for len(buf) > 0 && !utf8.Valid(buf) {
+			buf = buf[:len(buf)-1]
+		}
+	}
+	s := strings.Join(strings.Fields(strings.ToValidUTF8(string(buf), "")), " ")
+	if truncated {
+		s += "..."
+	}
+	return s
+}
+
 // IsRetryable reports whether the operation that caused this error may succeed on retry.
 // Token expiration, network errors, server errors, and rate limits are considered retryable.
+// Blocked endpoints, empty responses, and a closed market are not: retrying will not help
+// until NEPSE or the market changes state.
 func (e *NepseError) IsRetryable() bool {
 	switch e.Type {
 	case ErrorTypeTokenExpired, ErrorTypeNetworkError, ErrorTypeInvalidServerResponse, ErrorTypeRateLimit:
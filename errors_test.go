@@ -0,0 +1,204 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNepseError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *NepseError
+		want string
+	}{
+		{"type only", &NepseError{Type: ErrorTypeNotFound}, "nepse: not_found"},
+		{"message", NewRateLimitError(), "nepse: rate limit exceeded"},
+		{"wrapped", NewInternalError("failed to decode response", errors.New("bad json")), "nepse: failed to decode response: bad json"},
+		{
+			"request details",
+			&NepseError{
+				Type: ErrorTypeRateLimit, Message: "rate limit exceeded",
+				StatusCode: 429, Method: "GET", Endpoint: "/api/nots/top-ten/top-gainer",
+				Attempts: 3, Elapsed: 1234 * time.Millisecond, RetryAfter: 5 * time.Second,
+				Body: `{"message":"slow down"}`,
+			},
+			`nepse: rate limit exceeded (GET /api/nots/top-ten/top-gainer: status 429, 3 attempts, 1.234s, retry after 5s): "{\"message\":\"slow down\"}"`,
+		},
+		{
+			"network failure",
+			&NepseError{
+				Type: ErrorTypeNetworkError, Message: "network request failed", Err: errors.New("connection refused"),
+				Method: "POST", Endpoint: "/api/nots/graph/index/58", Attempts: 1, Elapsed: 2 * time.Millisecond,
+			},
+			"nepse: network request failed (POST /api/nots/graph/index/58: 1 attempt, 2ms): connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyExcerpt(t *testing.T) {
+	if got := bodyExcerpt(strings.NewReader("  {\"message\":\n\t\"Not Found\"}  ")); got != `{"message": "Not Found"}` {
+		t.Errorf("whitespace not collapsed: %q", got)
+	}
+
+	long := strings.Repeat("é", maxBodyExcerpt)
+	got := bodyExcerpt(strings.NewReader(long))
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("expected truncation marker, got %q", got)
+	}
+	if len(got) > maxBodyExcerpt+len("...") || !strings.HasPrefix(got, "éé") || strings.ContainsRune(got, '�') {
+		t.Errorf("bad truncation: %q", got)
+	}
+}
+
+func TestClient_ErrorCarriesRequestDetails(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tokenResponse())
+		case "/api/nots/security/99999":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Security not found"}`))
+		case "/api/nots/top-ten/top-gainer":
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewClient(&Options{
+		BaseURL:     server.URL,
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  0,
+		Config: &Config{
+			BaseURL:   server.URL,
+			Endpoints: DefaultEndpoints(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	_, err = client.Company(ctx, 99999)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	var nerr *NepseError
+	if !errors.As(err, &nerr) {
+		t.Fatalf("expected *NepseError, got %T", err)
+	}
+	if nerr.StatusCode != http.StatusNotFound || nerr.Method != http.MethodGet ||
+		nerr.Endpoint != "/api/nots/security/99999" || nerr.Attempts != 1 {
+		t.Errorf("unexpected details: %+v", nerr)
+	}
+	if nerr.Body != `{"message":"Security not found"}` {
+		t.Errorf("Body = %q", nerr.Body)
+	}
+	if !strings.Contains(err.Error(), "/api/nots/security/99999") || !strings.Contains(err.Error(), "Security not found") {
+		t.Errorf("Error() lacks request details: %v", err)
+	}
+	if strings.Contains(err.Error(), "Salter") {
+		t.Errorf("Error() leaks credentials: %v", err)
+	}
+
+	_, err = client.TopGainers(ctx)
+	if !errors.As(err, &nerr) || !errors.Is(err, ErrRateLimit) {
+		t.Fatalf("expected rate limit *NepseError, got %v", err)
+	}
+	if nerr.RetryAfter != 7*time.Second {
+		t.Errorf("RetryAfter = %v, want 7s", nerr.RetryAfter)
+	}
+}
+
+func TestClient_DedicatedErrorCategories(t *testing.T) {
+	var marketOpen atomic.Bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			json.NewEncoder(w).Encode(tokenResponse())
+		case "/api/nots/nepse-data/market-open":
+			status := MarketStatus{IsOpen: "CLOSE", AsOf: DateTimeOf(time.Date(2026, 1, 2, 15, 0, 0, 0, NPT)), ID: 61}
+			if marketOpen.Load() {
+				status.IsOpen = "OPEN"
+			}
+			json.NewEncoder(w).Encode(status)
+		case "/api/nots/security/floorsheet/131":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Forbidden"}`))
+		case "/api/nots/lives-market", "/api/nots/nepse-data/today-price", "/api/nots/nepse-index":
+			w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewClient(&Options{
+		BaseURL:     server.URL,
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  0,
+		Config: &Config{
+			BaseURL:   server.URL,
+			Endpoints: DefaultEndpoints(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	_, err = client.FloorSheetOf(ctx, 131, NewDate(2026, 1, 2))
+	if !errors.Is(err, ErrEndpointBlocked) || !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("FloorSheetOf: expected ErrEndpointBlocked wrapping ErrUnauthorized, got %v", err)
+	}
+	var nerr *NepseError
+	if errors.As(err, &nerr) && nerr.IsRetryable() {
+		t.Errorf("FloorSheetOf: blocked endpoint should not be retryable: %+v", nerr)
+	}
+	if cause, ok := errors.Unwrap(err).(*NepseError); !ok || cause.StatusCode != http.StatusForbidden {
+		t.Errorf("FloorSheetOf: expected the 403 details on the wrapped cause, got %#v", errors.Unwrap(err))
+	}
+	if msg := err.Error(); strings.Count(msg, "status 403") != 1 || strings.Count(msg, "message") != 1 {
+		t.Errorf("FloorSheetOf: request details should appear once, got %q", msg)
+	}
+
+	if _, err := client.TodaysPrices(ctx, Date{}); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("TodaysPrices: expected ErrEmptyResponse, got %v", err)
+	}
+	if _, err := client.SubIndices(ctx); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("SubIndices: expected ErrEmptyResponse, got %v", err)
+	}
+	if _, err := client.LiveMarket(ctx); !errors.Is(err, ErrMarketClosed) {
+		t.Errorf("LiveMarket while closed: expected ErrMarketClosed, got %v", err)
+	}
+
+	marketOpen.Store(true)
+	if _, err := client.LiveMarket(ctx); !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("LiveMarket while open: expected ErrEmptyResponse, got %v", err)
+	}
+}
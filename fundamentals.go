@@ -7,6 +7,8 @@ import (
 
 // CompanyProfile returns detailed profile information for a security.
 func (c *Client) CompanyProfile(ctx context.Context, securityID int32) (*CompanyProfile, error) {
+	ctx = withOperation(ctx, "CompanyProfile")
+
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.CompanyProfile, securityID)
 
 	var profile CompanyProfile
@@ -18,6 +20,8 @@ func (c *Client) CompanyProfile(ctx context.Context, securityID int32) (*Company
 
 // CompanyProfileBySymbol returns detailed profile information for a security by symbol.
 func (c *Client) CompanyProfileBySymbol(ctx context.Context, symbol string) (*CompanyProfile, error) {
+	ctx = withOperation(ctx, "CompanyProfileBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
@@ -27,6 +31,8 @@ func (c *Client) CompanyProfileBySymbol(ctx context.Context, symbol string) (*Co
 
 // BoardOfDirectors returns the board of directors for a security.
 func (c *Client) BoardOfDirectors(ctx context.Context, securityID int32) ([]BoardMember, error) {
+	ctx = withOperation(ctx, "BoardOfDirectors")
+
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.BoardOfDirectors, securityID)
 
 	var members []BoardMember
@@ -38,6 +44,8 @@ func (c *Client) BoardOfDirectors(ctx context.Context, securityID int32) ([]Boar
 
 // BoardOfDirectorsBySymbol returns the board of directors for a security by symbol.
 func (c *Client) BoardOfDirectorsBySymbol(ctx context.Context, symbol string) ([]BoardMember, error) {
+	ctx = withOperation(ctx, "BoardOfDirectorsBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
@@ -47,6 +55,8 @@ func (c *Client) BoardOfDirectorsBySymbol(ctx context.Context, symbol string) ([
 
 // CorporateActions returns corporate actions (bonus, rights, dividends) for a security.
 func (c *Client) CorporateActions(ctx context.Context, securityID int32) ([]CorporateAction, error) {
+	ctx = withOperation(ctx, "CorporateActions")
+
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.CorporateActions, securityID)
 
 	var actions []CorporateAction
@@ -58,6 +68,8 @@ func (c *Client) CorporateActions(ctx context.Context, securityID int32) ([]Corp
 
 // CorporateActionsBySymbol returns corporate actions for a security by symbol.
 func (c *Client) CorporateActionsBySymbol(ctx context.Context, symbol string) ([]CorporateAction, error) {
+	ctx = withOperation(ctx, "CorporateActionsBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
@@ -67,6 +79,8 @@ func (c *Client) CorporateActionsBySymbol(ctx context.Context, symbol string) ([
 
 // Reports returns quarterly and annual reports for a security.
 func (c *Client) Reports(ctx context.Context, securityID int32) ([]Report, error) {
+	ctx = withOperation(ctx, "Reports")
+
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.Reports, securityID)
 
 	var reports []Report
@@ -78,6 +92,8 @@ func (c *Client) Reports(ctx context.Context, securityID int32) ([]Report, error
 
 // ReportsBySymbol returns quarterly and annual reports for a security by symbol.
 func (c *Client) ReportsBySymbol(ctx context.Context, symbol string) ([]Report, error) {
+	ctx = withOperation(ctx, "ReportsBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
@@ -87,6 +103,8 @@ func (c *Client) ReportsBySymbol(ctx context.Context, symbol string) ([]Report,
 
 // Dividends returns dividend history for a security.
 func (c *Client) Dividends(ctx context.Context, securityID int32) ([]Dividend, error) {
+	ctx = withOperation(ctx, "Dividends")
+
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.Dividend, securityID)
 
 	var dividends []Dividend
@@ -98,6 +116,8 @@ func (c *Client) Dividends(ctx context.Context, securityID int32) ([]Dividend, e
 
 // DividendsBySymbol returns dividend history for a security by symbol.
 func (c *Client) DividendsBySymbol(ctx context.Context, symbol string) ([]Dividend, error) {
+	ctx = withOperation(ctx, "DividendsBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
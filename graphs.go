@@ -2,51 +2,119 @@ package nepse
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/voidarchive/go-nepse/internal/auth"
+	"github.com/voidarchive/go-nepse/payload"
 )
 
-// dummyData is a static array used by NEPSE's obfuscation algorithm
-// to compute POST payload IDs for graph endpoints.
-var dummyData = [100]int{
-	147, 117, 239, 143, 157, 312, 161, 612, 512, 804,
-	411, 527, 170, 511, 421, 667, 764, 621, 301, 106,
-	133, 793, 411, 511, 312, 423, 344, 346, 653, 758,
-	342, 222, 236, 811, 711, 611, 122, 447, 128, 199,
-	183, 135, 489, 703, 800, 745, 152, 863, 134, 211,
-	142, 564, 375, 793, 212, 153, 138, 153, 648, 611,
-	151, 649, 318, 143, 117, 756, 119, 141, 717, 113,
-	112, 146, 162, 660, 693, 261, 362, 354, 251, 641,
-	157, 178, 631, 192, 734, 445, 192, 883, 187, 122,
-	591, 731, 852, 384, 565, 596, 451, 772, 624, 691,
+// nptDate returns the date at t in Nepal as YYYY-MM-DD.
+func nptDate(t time.Time) string {
+	return t.In(payload.NPT).Format(time.DateOnly)
+}
+
+// payloadCache holds graph payload IDs between calls. The base ID depends
+// only on the NPT date and the market status ID, so MarketStatus is fetched
+// once a day rather than before every POST. The index ID also depends on the
+// salts and is recomputed when the token rotates.
+type payloadCache struct {
+	sf singleflight.Group
+
+	mu       sync.Mutex
+	date     string // NPT date the base ID was computed for; empty when invalid
+	day      int
+	statusID int32
+	base     int
+	salts    auth.Salts // salts the index ID was computed with
+	index    int
+	hasIndex bool
 }
 
+// invalidate drops the cached IDs, so the next POST refetches the market status.
+func (p *payloadCache) invalidate() {
+	p.mu.Lock()
+	p.date = ""
+	p.hasIndex = false
+	p.mu.Unlock()
+}
+
+// observeStatus invalidates the cache when the market status ID differs
+// from the one the base ID was computed with.
+func (p *payloadCache) observeStatus(id int32) {
+	p.mu.Lock()
+	if p.date != "" && p.statusID != id {
+		p.date = ""
+		p.hasIndex = false
+	}
+	p.mu.Unlock()
+}
+
+// payloadStatusTimeout bounds the shared MarketStatus fetch behind a base
+// payload ID. Like the registry load, it runs detached from the callers'
+// contexts so that one caller giving up does not fail the others.
+const payloadStatusTimeout = time.Minute
+
 // computeBasePayloadID computes the base payload value used by graph endpoints.
 // Returns: dummyData[dummyID] + dummyID + 2 * day
 func (c *Client) computeBasePayloadID(ctx context.Context) (int, int, error) {
-	// Get the dummy ID from market status
-	status, err := c.MarketStatus(ctx)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get market status: %w", err)
+	p := &c.payloadIDs
+	p.mu.Lock()
+	if p.date == nptDate(c.authManager.ServerNow()) {
+		base, day := p.base, p.day
+		p.mu.Unlock()
+		return base, day, nil
 	}
-	dummyID := int(status.ID)
+	p.mu.Unlock()
+
+	// Concurrent misses share one MarketStatus request. Each caller waits
+	// only as long as its own ctx allows.
+	ch := p.sf.DoChan("base", func() (any, error) {
+		sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), payloadStatusTimeout)
+		defer cancel()
+
+		// Get the dummy ID from market status
+		status, err := c.MarketStatus(sctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get market status: %w", err)
+		}
 
-	// Ensure dummyID is within bounds
-	if dummyID < 0 || dummyID >= len(dummyData) {
-		dummyID = dummyID % len(dummyData)
-		if dummyID < 0 {
-			dummyID += len(dummyData)
+		// Read NEPSE's clock only now that a token has measured the offset,
+		// so a skewed host near midnight still picks NEPSE's day.
+		now := c.authManager.ServerNow()
+		date, day := nptDate(now), payload.Day(now)
+		e := c.payload.Base(int(status.ID), day)
+
+		p.mu.Lock()
+		p.date, p.day, p.statusID, p.base = date, day, status.ID, e
+		p.hasIndex = false
+		p.mu.Unlock()
+
+		c.logger.DebugContext(sctx, "nepse base payload id computed",
+			slog.Int("status_id", int(status.ID)),
+			slog.Int("day", day),
+			slog.Int("payload_id", e),
+		)
+		return nil, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return 0, 0, res.Err
 		}
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
 	}
 
-	// Get current day of month in Nepal timezone (NEPSE expects NPT)
-	loc, _ := time.LoadLocation("Asia/Kathmandu")
-	day := time.Now().In(loc).Day()
-
-	// Compute base value: dummyData[dummyID] + dummyID + 2 * day
-	e := dummyData[dummyID] + dummyID + 2*day
-
-	return e, day, nil
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.base, p.day, nil
 }
 
 // computeIndexGraphPayloadID computes the POST payload ID for index graph endpoints.
@@ -63,19 +131,35 @@ func (c *Client) computeIndexGraphPayloadID(ctx context.Context) (int, error) {
 		return 0, fmt.Errorf("failed to get salts: %w", err)
 	}
 
-	// Compute final payload ID using salt values
-	// Logic: if (e % 10 < 5) use salts[3] * day - salts[2], else use salts[1] * day - salts[0]
-	// Python uses 1-indexed array, so: salts[3] = Salt4, salts[1] = Salt2, salts[2] = Salt3, salts[0] = Salt1
-	var payloadID int
-	if e%10 < 5 {
-		payloadID = e + salts.Salt4*day - salts.Salt3
-	} else {
-		payloadID = e + salts.Salt2*day - salts.Salt1
+	p := &c.payloadIDs
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.hasIndex && p.salts == salts && p.base == e {
+		return p.index, nil
 	}
 
+	payloadID := payload.Index(e, day, salts)
+	p.salts, p.index, p.hasIndex = salts, payloadID, true
+
+	c.logger.DebugContext(ctx, "nepse index graph payload id computed",
+		slog.Int("base", e),
+		slog.Int("payload_id", payloadID),
+	)
 	return payloadID, nil
 }
 
+// payloadRejected drops the cached payload IDs when NEPSE refuses a payload
+// POST, in case the market status ID changed, and returns err unchanged.
+// NEPSE rejects a wrong payload ID with 400; other errors, such as a 404 for
+// an unknown security, leave the cache alone.
+func (c *Client) payloadRejected(err error) error {
+	var nerr *NepseError
+	if errors.As(err, &nerr) && nerr.StatusCode == http.StatusBadRequest {
+		c.payloadIDs.invalidate()
+	}
+	return err
+}
+
 // computeScripGraphPayloadID computes the POST payload ID for security/scrip graph endpoints.
 // Uses only the base calculation without salt adjustment.
 func (c *Client) computeScripGraphPayloadID(ctx context.Context) (int, error) {
@@ -159,105 +243,131 @@ func (c *Client) indexEndpoint(indexType IndexType) string {
 
 // DailyIndexGraph returns intraday graph data points for any market index.
 func (c *Client) DailyIndexGraph(ctx context.Context, indexType IndexType) (*GraphResponse, error) {
-	payloadID, err := c.computeIndexGraphPayloadID(ctx)
-	if err != nil {
-		return nil, err
-	}
+	ctx = withOperation(ctx, "DailyIndexGraph")
+
+	// The index payload depends on the token's salts, so it is rebuilt if
+	// the POST is retried with a fresh token.
+	body := bodyFunc(func(ctx context.Context) (any, error) {
+		payloadID, err := c.computeIndexGraphPayloadID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return graphPostPayload{ID: payloadID}, nil
+	})
 
 	var arr []GraphDataPoint
-	if err := c.apiPostRequest(ctx, c.indexEndpoint(indexType), graphPostPayload{ID: payloadID}, &arr); err != nil {
-		return nil, err
+	if err := c.apiPostRequest(ctx, c.indexEndpoint(indexType), body, &arr); err != nil {
+		return nil, c.payloadRejected(err)
 	}
 	return &GraphResponse{Data: arr}, nil
 }
 
 // DailyNepseIndexGraph returns intraday graph data for the main NEPSE index.
 func (c *Client) DailyNepseIndexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyNepseIndexGraph")
 	return c.DailyIndexGraph(ctx, IndexNepse)
 }
 
 // DailySensitiveIndexGraph returns intraday graph data for the sensitive index.
 func (c *Client) DailySensitiveIndexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailySensitiveIndexGraph")
 	return c.DailyIndexGraph(ctx, IndexSensitive)
 }
 
 // DailyFloatIndexGraph returns intraday graph data for the float index.
 func (c *Client) DailyFloatIndexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyFloatIndexGraph")
 	return c.DailyIndexGraph(ctx, IndexFloat)
 }
 
 // DailySensitiveFloatIndexGraph returns intraday graph data for the sensitive float index.
 func (c *Client) DailySensitiveFloatIndexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailySensitiveFloatIndexGraph")
 	return c.DailyIndexGraph(ctx, IndexSensitiveFloat)
 }
 
 // DailyBankSubindexGraph returns intraday graph data for the banking sector sub-index.
 func (c *Client) DailyBankSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyBankSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexBanking)
 }
 
 // DailyDevelopmentBankSubindexGraph returns intraday graph data for the development bank sector.
 func (c *Client) DailyDevelopmentBankSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyDevelopmentBankSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexDevBank)
 }
 
 // DailyFinanceSubindexGraph returns intraday graph data for the finance sector.
 func (c *Client) DailyFinanceSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyFinanceSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexFinance)
 }
 
 // DailyHotelTourismSubindexGraph returns intraday graph data for the hotel & tourism sector.
 func (c *Client) DailyHotelTourismSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyHotelTourismSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexHotelTourism)
 }
 
 // DailyHydroSubindexGraph returns intraday graph data for the hydropower sector.
 func (c *Client) DailyHydroSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyHydroSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexHydro)
 }
 
 // DailyInvestmentSubindexGraph returns intraday graph data for the investment sector.
 func (c *Client) DailyInvestmentSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyInvestmentSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexInvestment)
 }
 
 // DailyLifeInsuranceSubindexGraph returns intraday graph data for the life insurance sector.
 func (c *Client) DailyLifeInsuranceSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyLifeInsuranceSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexLifeInsurance)
 }
 
 // DailyManufacturingSubindexGraph returns intraday graph data for the manufacturing sector.
 func (c *Client) DailyManufacturingSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyManufacturingSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexManufacturing)
 }
 
 // DailyMicrofinanceSubindexGraph returns intraday graph data for the microfinance sector.
 func (c *Client) DailyMicrofinanceSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyMicrofinanceSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexMicrofinance)
 }
 
 // DailyMutualfundSubindexGraph returns intraday graph data for the mutual fund sector.
 func (c *Client) DailyMutualfundSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyMutualfundSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexMutualFund)
 }
 
 // DailyNonLifeInsuranceSubindexGraph returns intraday graph data for the non-life insurance sector.
 func (c *Client) DailyNonLifeInsuranceSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyNonLifeInsuranceSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexNonLifeInsurance)
 }
 
 // DailyOthersSubindexGraph returns intraday graph data for the others sector.
 func (c *Client) DailyOthersSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyOthersSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexOthers)
 }
 
 // DailyTradingSubindexGraph returns intraday graph data for the trading sector.
 func (c *Client) DailyTradingSubindexGraph(ctx context.Context) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyTradingSubindexGraph")
 	return c.DailyIndexGraph(ctx, IndexTrading)
 }
 
 // DailyScripGraph returns intraday price graph data for a specific security.
 func (c *Client) DailyScripGraph(ctx context.Context, securityID int32) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyScripGraph")
+
 	payloadID, err := c.computeScripGraphPayloadID(ctx)
 	if err != nil {
 		return nil, err
@@ -266,13 +376,15 @@ func (c *Client) DailyScripGraph(ctx context.Context, securityID int32) (*GraphR
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.CompanyDailyGraph, securityID)
 	var arr []GraphDataPoint
 	if err := c.apiPostRequest(ctx, endpoint, graphPostPayload{ID: payloadID}, &arr); err != nil {
-		return nil, err
+		return nil, c.payloadRejected(err)
 	}
 	return &GraphResponse{Data: arr}, nil
 }
 
 // DailyScripGraphBySymbol returns intraday price graph data for a security by ticker symbol.
 func (c *Client) DailyScripGraphBySymbol(ctx context.Context, symbol string) (*GraphResponse, error) {
+	ctx = withOperation(ctx, "DailyScripGraphBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
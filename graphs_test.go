@@ -0,0 +1,255 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/go-nepse/internal/auth"
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+// manualClock is a Clock that only moves when told to.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// payloadServer records the payload IDs POSTed to graph endpoints and how
+// often the market status was fetched. NEPSE's clock is the test's clock.
+type payloadServer struct {
+	*httptest.Server
+	clock *manualClock
+
+	statusID    atomic.Int32
+	statusHits  atomic.Int32
+	proves      atomic.Int32
+	rejectPosts atomic.Int32  // number of upcoming POSTs to answer with 400
+	expireNext  atomic.Bool   // answer the next POST with 401
+	holdStatus  chan struct{} // if set, market status waits until it is closed
+
+	mu  sync.Mutex
+	ids []int
+}
+
+func newPayloadServer(t *testing.T) (*payloadServer, *Client) {
+	t.Helper()
+	s := &payloadServer{clock: &manualClock{now: time.Date(2026, 1, 1, 6, 0, 0, 0, time.UTC)}}
+	s.statusID.Store(61)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s, newTestClient(t, s.Server, Options{Clock: s.clock})
+}
+
+// salts returns the salts issued by the nth prove, counting from 1.
+func proveSalts(n int32) auth.Salts {
+	return auth.Salts{Salt1: 1234 * int(n), Salt2: 5678, Salt3: 9012, Salt4: 3456 + int(n), Salt5: 7890}
+}
+
+func (s *payloadServer) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case "/api/authenticate/prove":
+		salts := proveSalts(s.proves.Add(1))
+		resp := tokenResponse()
+		resp.Salt1, resp.Salt4 = salts.Salt1, salts.Salt4
+		resp.ServerTime = s.clock.Now().UnixMilli()
+		json.NewEncoder(w).Encode(resp)
+		return
+	case "/api/nots/nepse-data/market-open":
+		s.statusHits.Add(1)
+		if s.holdStatus != nil {
+			<-s.holdStatus
+		}
+		json.NewEncoder(w).Encode(MarketStatus{IsOpen: "OPEN", ID: s.statusID.Load()})
+		return
+	}
+
+	if r.Method != http.MethodPost || r.URL.Path == "/api/authenticate/refresh-token" {
+		http.NotFound(w, r)
+		return
+	}
+	if s.expireNext.CompareAndSwap(true, false) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var body graphPostPayload
+	json.NewDecoder(r.Body).Decode(&body)
+	s.mu.Lock()
+	s.ids = append(s.ids, body.ID)
+	s.mu.Unlock()
+	if s.rejectPosts.Load() > 0 {
+		s.rejectPosts.Add(-1)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if r.URL.Path == "/api/nots/security/999" {
+		http.NotFound(w, r)
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/api/nots/security/") {
+		w.Write([]byte(`{}`))
+		return
+	}
+	w.Write([]byte(`[]`))
+}
+
+func (s *payloadServer) lastID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ids[len(s.ids)-1]
+}
+
+func TestClient_PayloadIDCachedPerDay(t *testing.T) {
+	srv, client := newPayloadServer(t)
+	ctx := context.Background()
+
+	for range 5 {
+		if _, err := client.DailyScripGraph(ctx, 131); err != nil {
+			t.Fatalf("DailyScripGraph failed: %v", err)
+		}
+	}
+	if _, err := client.SecurityDetail(ctx, 131); err != nil {
+		t.Fatalf("SecurityDetail failed: %v", err)
+	}
+	if n := srv.statusHits.Load(); n != 1 {
+		t.Errorf("market status fetched %d times for one day, want 1", n)
+	}
+	if got, want := srv.lastID(), payload.Base(61, 1); got != want {
+		t.Errorf("payload id = %d, want %d", got, want)
+	}
+
+	// The next NPT day needs a new base ID.
+	srv.clock.Advance(24 * time.Hour)
+	if _, err := client.DailyScripGraph(ctx, 131); err != nil {
+		t.Fatalf("DailyScripGraph failed: %v", err)
+	}
+	if n := srv.statusHits.Load(); n != 2 {
+		t.Errorf("market status fetched %d times over two days, want 2", n)
+	}
+	if got, want := srv.lastID(), payload.Base(61, 2); got != want {
+		t.Errorf("payload id on day 2 = %d, want %d", got, want)
+	}
+}
+
+func TestClient_PayloadIDInvalidation(t *testing.T) {
+	srv, client := newPayloadServer(t)
+	ctx := context.Background()
+
+	if _, err := client.DailyScripGraph(ctx, 131); err != nil {
+		t.Fatalf("DailyScripGraph failed: %v", err)
+	}
+
+	// A market status with a new ID, fetched by anyone, invalidates the cache.
+	srv.statusID.Store(62)
+	if _, err := client.MarketStatus(ctx); err != nil {
+		t.Fatalf("MarketStatus failed: %v", err)
+	}
+	if _, err := client.DailyScripGraph(ctx, 131); err != nil {
+		t.Fatalf("DailyScripGraph failed: %v", err)
+	}
+	if got, want := srv.lastID(), payload.Base(62, 1); got != want {
+		t.Errorf("payload id after status change = %d, want %d", got, want)
+	}
+	hits := srv.statusHits.Load()
+
+	// A rejected POST invalidates it too.
+	srv.rejectPosts.Store(1)
+	if _, err := client.DailyScripGraph(ctx, 131); err == nil {
+		t.Fatal("expected rejected POST to fail")
+	}
+	if _, err := client.DailyScripGraph(ctx, 131); err != nil {
+		t.Fatalf("DailyScripGraph failed: %v", err)
+	}
+	if n := srv.statusHits.Load(); n != hits+1 {
+		t.Errorf("market status fetched %d more times after rejection, want 1", n-hits)
+	}
+
+	// An unknown security is not a rejected payload and keeps the cache.
+	hits = srv.statusHits.Load()
+	if _, err := client.SecurityDetail(ctx, 999); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown security, got %v", err)
+	}
+	if _, err := client.DailyScripGraph(ctx, 131); err != nil {
+		t.Fatalf("DailyScripGraph failed: %v", err)
+	}
+	if n := srv.statusHits.Load(); n != hits {
+		t.Errorf("market status fetched %d more times after a 404, want 0", n-hits)
+	}
+}
+
+func TestClient_PayloadIDCanceledCallerDoesNotFailOthers(t *testing.T) {
+	srv, client := newPayloadServer(t)
+	srv.holdStatus = make(chan struct{})
+
+	cctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.DailyScripGraph(cctx, 131)
+		first <- err
+	}()
+	second := make(chan error, 1)
+	go func() {
+		_, err := client.DailyScripGraph(context.Background(), 131)
+		second <- err
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for the canceled caller, got %v", err)
+	}
+	close(srv.holdStatus)
+	if err := <-second; err != nil {
+		t.Errorf("expected the shared market status fetch to finish for the other caller, got %v", err)
+	}
+	if n := srv.statusHits.Load(); n != 1 {
+		t.Errorf("market status fetched %d times, want 1", n)
+	}
+}
+
+func TestClient_IndexPayloadFollowsToken(t *testing.T) {
+	srv, client := newPayloadServer(t)
+	ctx := context.Background()
+	base := payload.Base(61, 1)
+
+	for range 3 {
+		if _, err := client.DailyNepseIndexGraph(ctx); err != nil {
+			t.Fatalf("DailyNepseIndexGraph failed: %v", err)
+		}
+	}
+	if got, want := srv.lastID(), payload.Index(base, 1, proveSalts(1)); got != want {
+		t.Errorf("index payload id = %d, want %d", got, want)
+	}
+
+	// A 401 rotates the token; the retried POST must use the new salts.
+	srv.expireNext.Store(true)
+	if _, err := client.DailyNepseIndexGraph(ctx); err != nil {
+		t.Fatalf("DailyNepseIndexGraph after token rotation failed: %v", err)
+	}
+	if got, want := srv.lastID(), payload.Index(base, 1, proveSalts(2)); got != want {
+		t.Errorf("index payload id after rotation = %d, want %d", got, want)
+	}
+	if n := srv.statusHits.Load(); n != 1 {
+		t.Errorf("market status fetched %d times, want 1", n)
+	}
+}
@@ -0,0 +1,39 @@
+package auth
+
+import "time"
+
+// Clock tells the time. Substitute one with [WithClock] to pin time in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// WithClock sets the clock used for token expiry and [Manager.ServerNow].
+// Background refresh timers still run on real time.
+func WithClock(c Clock) Option {
+	return func(m *Manager) {
+		if c != nil {
+			m.clock = c
+		}
+	}
+}
+
+// ServerNow estimates NEPSE's current time: the manager's clock adjusted by
+// the offset measured from the last token response's serverTime. Before the
+// first token it is the manager's clock unadjusted.
+func (m *Manager) ServerNow() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.clock.Now().Add(m.offset)
+}
+
+// ClockOffset returns NEPSE's clock minus the local clock, as measured at
+// the last token refresh.
+func (m *Manager) ClockOffset() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.offset
+}
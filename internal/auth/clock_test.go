@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock that only moves when told to.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestManager_ClockSkew(t *testing.T) {
+	local := &manualClock{now: time.Date(2026, 1, 1, 18, 10, 0, 0, time.UTC)}
+	server := local.Now().Add(-10 * time.Minute) // host clock runs 10 minutes fast
+
+	mock := &mockNepseHTTP{tokenFunc: func(ctx context.Context) (*TokenResponse, error) {
+		return &TokenResponse{
+			Salt1: 1234, Salt2: 5678, Salt3: 9012, Salt4: 3456, Salt5: 7890,
+			AccessToken: "testXtokenYwithZjunkAcharsB",
+			ServerTime:  server.UnixMilli(),
+		}, nil
+	}}
+	manager, err := NewManager(mock, WithClock(local))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+
+	if got := manager.ServerNow(); !got.Equal(local.Now()) {
+		t.Errorf("ServerNow() before first token = %v, want local time %v", got, local.Now())
+	}
+
+	ctx := context.Background()
+	if _, err := manager.AccessToken(ctx); err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	if got := manager.ClockOffset(); got != -10*time.Minute {
+		t.Errorf("ClockOffset() = %v, want -10m", got)
+	}
+	if got := manager.ServerNow(); !got.Equal(server) {
+		t.Errorf("ServerNow() = %v, want %v", got, server)
+	}
+
+	// Without compensation the token would look 10 minutes old and be
+	// refetched on every call.
+	local.Advance(DefaultTokenTTL - time.Second)
+	if _, err := manager.AccessToken(ctx); err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	if n := mock.callCount.Load(); n != 1 {
+		t.Errorf("expected cached token within TTL, got %d fetches", n)
+	}
+	if age := manager.Stats().TokenAge; age != DefaultTokenTTL-time.Second {
+		t.Errorf("TokenAge = %v, want %v", age, DefaultTokenTTL-time.Second)
+	}
+
+	local.Advance(2 * time.Second)
+	if _, err := manager.AccessToken(ctx); err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	if n := mock.callCount.Load(); n != 2 {
+		t.Errorf("expected refetch after TTL, got %d fetches", n)
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/singleflight"
+
+	"github.com/voidarchive/go-nepse/payload"
 )
 
 // DefaultTokenTTL defines when to proactively refresh tokens.
@@ -24,6 +28,27 @@ type NepseHTTP interface {
 	Token(ctx context.Context) (*TokenResponse, error)
 }
 
+// TokenRenewer is implemented by NepseHTTP clients that can renew tokens via
+// NEPSE's /api/authenticate/refresh-token endpoint. The Manager uses it when
+// available and falls back to [NepseHTTP.Token] when the renewal fails for
+// any reason other than the context ending.
+type TokenRenewer interface {
+	// RenewToken exchanges a decoded refresh token for a new token response.
+	// It returns an error matching [ErrRefreshRejected] when NEPSE refuses
+	// the refresh token, and [ErrRefreshUnsupported] when the endpoint is
+	// not served at all.
+	RenewToken(ctx context.Context, refreshToken string) (*TokenResponse, error)
+}
+
+var (
+	// ErrRefreshRejected reports that NEPSE refused a refresh token.
+	ErrRefreshRejected = errors.New("refresh token rejected")
+
+	// ErrRefreshUnsupported reports that the server does not serve the
+	// refresh endpoint. The Manager stops renewing for the rest of its life.
+	ErrRefreshUnsupported = errors.New("token refresh not supported")
+)
+
 // TokenResponse is the JSON structure from /api/authenticate/prove.
 // Salt values are used to compute which characters to strip from tokens.
 type TokenResponse struct {
@@ -39,50 +64,117 @@ type TokenResponse struct {
 
 // Salts holds the 5 salt values from the NEPSE token response.
 // These are used to compute POST payload IDs for certain endpoints.
-type Salts struct {
-	Salt1 int
-	Salt2 int
-	Salt3 int
-	Salt4 int
-	Salt5 int
-}
+type Salts = payload.Salts
 
 // Manager provides thread-safe access to NEPSE authentication tokens.
 // It uses singleflight to prevent thundering herd when multiple goroutines
 // request tokens simultaneously during refresh.
 type Manager struct {
 	http   NepseHTTP
-	parser *tokenParser
+	parser tokenIndexer
+	module []byte // replacement css.wasm; nil uses the embedded module
+	logger *slog.Logger
 
 	maxUpdatePeriod time.Duration
 
-	mu          sync.RWMutex
-	accessToken string
-	salts       Salts
-	tokenTS     time.Time
+	clock Clock
+
+	mu           sync.RWMutex
+	accessToken  string
+	refreshToken string // decoded; empty when none is held or its renewal failed
+	salts        Salts
+	tokenTS      time.Time     // when NEPSE issued the token, by its clock
+	offset       time.Duration // NEPSE's clock minus ours, measured at the last refresh
 
 	sf singleflight.Group
+
+	timing    refreshTiming
+	refresher *refresher // nil until StartBackgroundRefresh; guarded by mu
+	hook      func(RefreshEvent)
+	successes atomic.Uint64
+	failures  atomic.Uint64
+	proves    atomic.Uint64
+	renewals  atomic.Uint64
+	noRenew   atomic.Bool // the server does not serve the refresh endpoint
+	closeOnce sync.Once
 }
 
-// NewManager creates a Manager with the embedded WASM token parser.
-func NewManager(httpClient NepseHTTP) (*Manager, error) {
-	parser, err := newTokenParser()
-	if err != nil {
-		return nil, fmt.Errorf("init wasm parser: %w", err)
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithLogger sets the logger used for token refresh events.
+// Tokens are never logged in clear text.
+func WithLogger(l *slog.Logger) Option {
+	return func(m *Manager) {
+		if l != nil {
+			m.logger = l
+		}
+	}
+}
+
+// WithNativeParser decodes tokens with a pure-Go port of css.wasm instead of
+// running the embedded WASM module, avoiding the wazero runtime entirely.
+func WithNativeParser() Option {
+	return func(m *Manager) {
+		m.parser = nativeParser{}
+	}
+}
+
+// WithWASMModule decodes tokens with module instead of the embedded css.wasm,
+// for when NEPSE rotates its obfuscation. It has no effect together with
+// [WithNativeParser].
+func WithWASMModule(module []byte) Option {
+	return func(m *Manager) {
+		m.module = module
 	}
-	return &Manager{
+}
+
+// NewManager creates a Manager. Tokens are decoded with the embedded WASM
+// module unless [WithWASMModule] or [WithNativeParser] is given.
+func NewManager(httpClient NepseHTTP, opts ...Option) (*Manager, error) {
+	m := &Manager{
 		http:            httpClient,
-		parser:          parser,
+		logger:          slog.New(slog.DiscardHandler),
 		maxUpdatePeriod: DefaultTokenTTL,
-	}, nil
+		timing:          defaultRefreshTiming,
+		clock:           systemClock{},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.parser == nil {
+		module := m.module
+		if module == nil {
+			module = cssWasm
+		}
+		pool, err := poolFor(module)
+		if err != nil {
+			return nil, fmt.Errorf("init wasm parser: %w", err)
+		}
+		m.parser = pool
+	}
+	return m, nil
 }
 
-// Close must be called to release WASM runtime memory.
+// Close releases resources held by the manager. WASM instances belong to a
+// process-wide pool shared by every Manager using the same module; Close
+// drops this Manager's reference, and the last one frees a replacement
+// module. It stops the background refresher, if any, and waits for it to exit.
 func (m *Manager) Close() error {
-	if m.parser != nil {
-		return m.parser.close()
-	}
-	return nil
+	var err error
+	m.closeOnce.Do(func() {
+		m.mu.Lock()
+		r := m.refresher
+		m.refresher = &refresher{} // makes later StartBackgroundRefresh calls no-ops
+		m.mu.Unlock()
+		if r != nil {
+			r.stop()
+		}
+		if m.parser != nil {
+			err = m.parser.close()
+		}
+	})
+	return err
 }
 
 // AccessToken returns a valid access token, refreshing if expired.
@@ -142,56 +234,178 @@ func (m *Manager) isValid() bool {
 	if m.accessToken == "" || m.tokenTS.IsZero() {
 		return false
 	}
-	return time.Since(m.tokenTS) < m.maxUpdatePeriod
+	return m.tokenAgeLocked() < m.maxUpdatePeriod
+}
+
+// tokenAgeLocked returns the current token's age on NEPSE's clock.
+// m.mu must be held.
+func (m *Manager) tokenAgeLocked() time.Duration {
+	return m.clock.Now().Add(m.offset).Sub(m.tokenTS)
 }
 
 func (m *Manager) update(ctx context.Context) error {
+	return m.refresh(ctx, false)
+}
+
+// refresh fetches and decodes a new token. Unless background is set it does
+// nothing while the current token is valid; the background refresher renews
+// tokens before they expire. Concurrent callers share one fetch.
+func (m *Manager) refresh(ctx context.Context, background bool) error {
 	_, err, _ := m.sf.Do("token_update", func() (any, error) {
-		if m.isValid() {
+		if !background && m.isValid() {
 			return nil, nil
 		}
 
-		resp, err := m.http.Token(ctx)
+		start := time.Now()
+		err := m.fetch(ctx, start)
 		if err != nil {
-			return nil, fmt.Errorf("token update: %w", err)
+			m.failures.Add(1)
+		} else {
+			m.successes.Add(1)
 		}
-
-		access, ts, err := m.parseResponse(*resp)
-		if err != nil {
-			return nil, err
+		if m.hook != nil {
+			m.hook(RefreshEvent{
+				Background: background,
+				Err:        err,
+				Latency:    time.Since(start),
+				Stats:      m.Stats(),
+			})
 		}
+		return nil, err
+	})
+	return err
+}
 
-		m.mu.Lock()
-		m.accessToken = access
-		m.salts = Salts{
-			Salt1: resp.Salt1,
-			Salt2: resp.Salt2,
-			Salt3: resp.Salt3,
-			Salt4: resp.Salt4,
-			Salt5: resp.Salt5,
+// fetch requests a token from NEPSE and stores its decoded form.
+func (m *Manager) fetch(ctx context.Context, start time.Time) error {
+	tf, err := m.requestToken(ctx)
+	if err != nil {
+		m.logger.WarnContext(ctx, "nepse token refresh failed", slog.Any("error", err))
+		return fmt.Errorf("token update: %w", err)
+	}
+	resp, sent, received := tf.resp, tf.sent, tf.received
+
+	decoded, err := m.Decode(*resp)
+	if err != nil {
+		m.logger.WarnContext(ctx, "nepse token decode failed", slog.Any("error", err))
+		return err
+	}
+
+	if tf.renewed {
+		m.renewals.Add(1)
+	} else {
+		m.proves.Add(1)
+	}
+
+	m.mu.Lock()
+	m.accessToken = decoded.AccessToken
+	m.refreshToken = decoded.RefreshToken
+	m.salts = Salts{
+		Salt1: resp.Salt1,
+		Salt2: resp.Salt2,
+		Salt3: resp.Salt3,
+		Salt4: resp.Salt4,
+		Salt5: resp.Salt5,
+	}
+	if resp.ServerTime > 0 {
+		// NEPSE stamps serverTime while building the response; assume that
+		// happened halfway through the round trip.
+		m.tokenTS = time.UnixMilli(resp.ServerTime)
+		m.offset = m.tokenTS.Sub(sent.Add(received.Sub(sent) / 2))
+	} else {
+		m.tokenTS = received.Add(m.offset)
+	}
+	tokenTS, offset := m.tokenTS, m.offset
+	m.mu.Unlock()
+
+	m.logger.DebugContext(ctx, "nepse token refreshed",
+		slog.Bool("renewed", tf.renewed),
+		slog.Time("server_time", tokenTS),
+		slog.Duration("clock_offset", offset),
+		slog.Duration("latency", time.Since(start)),
+		slog.Any("token", Secret(decoded.AccessToken)),
+	)
+	return nil
+}
+
+// tokenFetch is a token response and when it was requested and received,
+// by the manager's clock.
+type tokenFetch struct {
+	resp           *TokenResponse
+	sent, received time.Time
+	renewed        bool // obtained with the refresh token rather than a full prove
+}
+
+// requestToken renews with the held refresh token when the client supports
+// it, and proves from scratch otherwise or when the renewal fails. A failed
+// renewal drops the refresh token so it is not retried; only the context
+// ending is returned without proving.
+func (m *Manager) requestToken(ctx context.Context) (tokenFetch, error) {
+	m.mu.RLock()
+	refresh := m.refreshToken
+	m.mu.RUnlock()
+
+	if renewer, ok := m.http.(TokenRenewer); ok && refresh != "" && !m.noRenew.Load() {
+		sent := m.clock.Now()
+		resp, err := renewer.RenewToken(ctx, refresh)
+		received := m.clock.Now()
+		if err == nil && resp.AccessToken != "" {
+			return tokenFetch{resp: resp, sent: sent, received: received, renewed: true}, nil
 		}
-		if ts > 0 {
-			m.tokenTS = time.Unix(ts, 0)
+		if ctx.Err() != nil {
+			return tokenFetch{}, ctx.Err()
+		}
+		if errors.Is(err, ErrRefreshUnsupported) {
+			m.noRenew.Store(true)
+			m.logger.InfoContext(ctx, "nepse refresh endpoint not served, renewal disabled", slog.Any("error", err))
 		} else {
-			m.tokenTS = time.Now()
+			m.logger.WarnContext(ctx, "nepse token renewal failed, proving again", slog.Any("error", err))
+		}
+		m.mu.Lock()
+		if m.refreshToken == refresh {
+			m.refreshToken = ""
 		}
 		m.mu.Unlock()
+	}
 
-		return nil, nil
-	})
-	return err
+	sent := m.clock.Now()
+	resp, err := m.http.Token(ctx)
+	received := m.clock.Now()
+	if err != nil {
+		return tokenFetch{}, err
+	}
+	return tokenFetch{resp: resp, sent: sent, received: received}, nil
+}
+
+// Decoded is a token response with NEPSE's junk characters located and
+// stripped.
+type Decoded struct {
+	AccessIndices  []int
+	RefreshIndices []int
+	AccessToken    string
+	RefreshToken   string // empty when the response carried none
 }
 
-func (m *Manager) parseResponse(tr TokenResponse) (string, int64, error) {
+// Decode strips the junk characters from both tokens in tr without storing
+// them. The Manager decodes its own tokens the same way; Decode exists for
+// diagnostics.
+func (m *Manager) Decode(tr TokenResponse) (Decoded, error) {
 	salts := [5]int{tr.Salt1, tr.Salt2, tr.Salt3, tr.Salt4, tr.Salt5}
 
 	idx, err := m.parser.indicesFromSalts(salts)
 	if err != nil {
-		return "", 0, fmt.Errorf("wasm parse: %w", err)
+		return Decoded{}, fmt.Errorf("wasm parse: %w", err)
 	}
 
-	parsedAccess := sliceSkipAt(tr.AccessToken, idx.access...)
-	return parsedAccess, tr.ServerTime / 1000, nil
+	d := Decoded{
+		AccessIndices:  idx.access,
+		RefreshIndices: idx.refresh,
+		AccessToken:    sliceSkipAt(tr.AccessToken, idx.access...),
+	}
+	if tr.RefreshToken != "" {
+		d.RefreshToken = sliceSkipAt(tr.RefreshToken, idx.refresh...)
+	}
+	return d, nil
 }
 
 // sliceSkipAt strips junk characters inserted by NEPSE's token obfuscation.
@@ -218,6 +432,20 @@ func sliceSkipAt(s string, positions ...int) string {
 	return string(out)
 }
 
+// Secret is a credential that must not appear in logs.
+// It implements [slog.LogValuer] so it is redacted by any handler.
+type Secret string
+
+// LogValue implements [slog.LogValuer].
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue("[REDACTED]")
+}
+
+// String returns a redacted placeholder so the secret is safe in fmt output too.
+func (s Secret) String() string {
+	return "[REDACTED]"
+}
+
 // SetAuthHeader adds the NEPSE-specific "Salter" authorization header.
 func SetAuthHeader(req *http.Request, token string) {
 	req.Header.Set("Authorization", "Salter "+token)
@@ -1,8 +1,12 @@
 package auth
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -535,3 +539,31 @@ func TestManager_ServerTimeHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestSecret_Redacted(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mock := &mockNepseHTTP{}
+	manager, err := NewManager(mock, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+
+	token, err := manager.AccessToken(context.Background())
+	if err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "nepse token refreshed") {
+		t.Errorf("expected refresh log, got %q", out)
+	}
+	if strings.Contains(out, token) {
+		t.Errorf("token leaked into log output: %q", out)
+	}
+	if got := fmt.Sprint(Secret(token)); got != "[REDACTED]" {
+		t.Errorf("fmt output = %q, want [REDACTED]", got)
+	}
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// TestNativeParser_MatchesWASM calls each WASM export and its Go port across
+// the argument space. Every export reads only its second argument, so the
+// others are randomised to prove they are ignored.
+func TestNativeParser_MatchesWASM(t *testing.T) {
+	wasm, err := newTokenParser()
+	if err != nil {
+		t.Fatalf("newTokenParser() failed: %v", err)
+	}
+	defer wasm.close()
+
+	funcs := []struct {
+		name   string
+		wasm   api.Function
+		native func(int32) int32
+	}{
+		{"cdx", wasm.cdx, cdx},
+		{"rdx", wasm.rdx, rdx},
+		{"bdx", wasm.bdx, bdx},
+		{"ndx", wasm.ndx, ndx},
+		{"mdx", wasm.mdx, mdx},
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	var args []int32
+	for b := int32(-2000); b <= 2000; b++ {
+		args = append(args, b)
+	}
+	for range 20000 {
+		args = append(args, int32(rng.Uint32()))
+	}
+	args = append(args, math.MinInt32, math.MinInt32+1, math.MaxInt32, math.MaxInt32-1)
+
+	for _, f := range funcs {
+		for _, b := range args {
+			want, err := wasm.call5(f.wasm, int(rng.Int32()), int(b), int(rng.Int32()), int(rng.Int32()), int(rng.Int32()))
+			if err != nil {
+				t.Fatalf("%s(%d) wasm call failed: %v", f.name, b, err)
+			}
+			if got := int(f.native(b)); got != want {
+				t.Fatalf("%s(%d) = %d, wasm returned %d", f.name, b, got, want)
+			}
+		}
+	}
+}
+
+func TestNativeParser_IndicesFromSaltsMatchesWASM(t *testing.T) {
+	wasm, err := newTokenParser()
+	if err != nil {
+		t.Fatalf("newTokenParser() failed: %v", err)
+	}
+	defer wasm.close()
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	salts := [][5]int{
+		{1234, 5678, 9012, 3456, 7890},
+		{0, 0, 0, 0, 0},
+		{-100, -200, -300, -400, -500},
+		{math.MaxInt32, math.MinInt32, 1, -1, 0},
+		{1 << 40, -(1 << 40), 7, 8, 9}, // wider than int32, truncated like WASM args
+	}
+	for range 5000 {
+		var s [5]int
+		for i := range s {
+			s[i] = rng.IntN(200000) - 100000
+		}
+		salts = append(salts, s)
+	}
+
+	for _, s := range salts {
+		want, err := wasm.indicesFromSalts(s)
+		if err != nil {
+			t.Fatalf("wasm indicesFromSalts(%v) failed: %v", s, err)
+		}
+		got, _ := nativeParser{}.indicesFromSalts(s)
+		if !slices.Equal(got.access, want.access) || !slices.Equal(got.refresh, want.refresh) {
+			t.Fatalf("indicesFromSalts(%v) = %+v, wasm returned %+v", s, got, want)
+		}
+	}
+}
+
+func TestManager_NativeParser(t *testing.T) {
+	mock := &mockNepseHTTP{}
+	manager, err := NewManager(mock, WithNativeParser())
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+
+	if _, ok := manager.parser.(nativeParser); !ok {
+		t.Fatalf("expected native parser, got %T", manager.parser)
+	}
+
+	reference, err := NewManager(&mockNepseHTTP{})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer reference.Close()
+
+	ctx := context.Background()
+	got, err := manager.AccessToken(ctx)
+	if err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	want, err := reference.AccessToken(ctx)
+	if err != nil {
+		t.Fatalf("reference AccessToken failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("native token %q != wasm token %q", got, want)
+	}
+}
+
+func BenchmarkNativeParser_IndicesFromSalts(b *testing.B) {
+	salts := [5]int{1234, 5678, 9012, 3456, 7890}
+	for i := 0; i < b.N; i++ {
+		_, _ = nativeParser{}.indicesFromSalts(salts)
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// junkChars are inserted into obfuscated tokens. Any byte works since the
+// decoder strips by position, not by value.
+const junkChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// ErrUnusableSalts is returned by [Obfuscator.Obfuscate] when the salts map
+// two junk characters to the same position or past the end of the token.
+// Callers generating salts should pick new ones and try again.
+var ErrUnusableSalts = errors.New("salts produce unusable token indices")
+
+// Obfuscator is the inverse of token decoding: it inserts junk characters
+// at the positions the WASM parser strips, producing a token response that
+// [Manager] decodes back to the original tokens. It exists for fake servers
+// and tests.
+type Obfuscator struct {
+	parser tokenIndexer
+}
+
+// NewObfuscator creates an Obfuscator backed by the embedded WASM parser,
+// sharing the process-wide instance pool with every [Manager].
+func NewObfuscator() (*Obfuscator, error) {
+	pool, err := poolFor(cssWasm)
+	if err != nil {
+		return nil, fmt.Errorf("init wasm parser: %w", err)
+	}
+	return &Obfuscator{parser: pool}, nil
+}
+
+// Close releases resources held by the Obfuscator.
+func (o *Obfuscator) Close() error {
+	return o.parser.close()
+}
+
+// Obfuscate returns a TokenResponse carrying salts and obfuscated forms of
+// access and refresh. ServerTime is left for the caller to set.
+func (o *Obfuscator) Obfuscate(salts Salts, access, refresh string) (TokenResponse, error) {
+	idx, err := o.parser.indicesFromSalts([5]int{salts.Salt1, salts.Salt2, salts.Salt3, salts.Salt4, salts.Salt5})
+	if err != nil {
+		return TokenResponse{}, fmt.Errorf("wasm parse: %w", err)
+	}
+
+	obfAccess, err := insertAt(access, idx.access)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	obfRefresh, err := insertAt(refresh, idx.refresh)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		Salt1:        salts.Salt1,
+		Salt2:        salts.Salt2,
+		Salt3:        salts.Salt3,
+		Salt4:        salts.Salt4,
+		Salt5:        salts.Salt5,
+		AccessToken:  obfAccess,
+		RefreshToken: obfRefresh,
+	}, nil
+}
+
+// insertAt is the inverse of [sliceSkipAt]: positions index into the result.
+func insertAt(s string, positions []int) (string, error) {
+	ps := make([]int, len(positions))
+	copy(ps, positions)
+	sort.Ints(ps)
+
+	n := len(s) + len(ps)
+	for i, p := range ps {
+		if p < 0 || p >= n || (i > 0 && p == ps[i-1]) {
+			return "", fmt.Errorf("%w: %v for length %d", ErrUnusableSalts, positions, len(s))
+		}
+	}
+
+	out := make([]byte, 0, n)
+	src, next := 0, 0
+	for i := 0; i < n; i++ {
+		if next < len(ps) && ps[next] == i {
+			out = append(out, junkChars[(i+next)%len(junkChars)])
+			next++
+			continue
+		}
+		out = append(out, s[src])
+		src++
+	}
+	return string(out), nil
+}
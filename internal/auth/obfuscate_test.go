@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObfuscator_RoundTrip(t *testing.T) {
+	obf, err := NewObfuscator()
+	if err != nil {
+		t.Fatalf("NewObfuscator failed: %v", err)
+	}
+	defer obf.Close()
+
+	access := strings.Repeat("eyJhbGciOiJIUzI1NiJ9", 8)
+	refresh := strings.Repeat("cmVmcmVzaA", 15)
+	salts := Salts{Salt1: 1234, Salt2: 5678, Salt3: 9012, Salt4: 3456, Salt5: 7890}
+
+	tr, err := obf.Obfuscate(salts, access, refresh)
+	if err != nil {
+		t.Fatalf("Obfuscate failed: %v", err)
+	}
+	if len(tr.AccessToken) != len(access)+5 {
+		t.Errorf("expected 5 junk characters, got length %d for %d", len(tr.AccessToken), len(access))
+	}
+	tr.ServerTime = time.Now().UnixMilli()
+
+	mock := &mockNepseHTTP{tokenFunc: func(context.Context) (*TokenResponse, error) { return &tr, nil }}
+	manager, err := NewManager(mock)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+
+	got, err := manager.AccessToken(context.Background())
+	if err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	if got != access {
+		t.Errorf("decoded token = %q, want %q", got, access)
+	}
+
+	if got := sliceSkipAt(tr.RefreshToken, mustIndices(t, obf, salts).refresh...); got != refresh {
+		t.Errorf("decoded refresh token = %q, want %q", got, refresh)
+	}
+}
+
+func TestObfuscator_UnusableSalts(t *testing.T) {
+	obf, err := NewObfuscator()
+	if err != nil {
+		t.Fatalf("NewObfuscator failed: %v", err)
+	}
+	defer obf.Close()
+
+	// Indices land around positions 26-122, far past the end of a short token.
+	_, err = obf.Obfuscate(Salts{Salt1: 1, Salt2: 2, Salt3: 3, Salt4: 4, Salt5: 5}, "short", "short")
+	if !errors.Is(err, ErrUnusableSalts) {
+		t.Errorf("expected ErrUnusableSalts, got %v", err)
+	}
+}
+
+func TestInsertAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions []int
+		wantErr   bool
+	}{
+		{"start and end", []int{0, 6}, false},
+		{"unsorted", []int{5, 1, 3}, false},
+		{"duplicate", []int{2, 2}, true},
+		{"out of range", []int{7}, true},
+		{"negative", []int{-1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := insertAt("abcdef", tt.positions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("insertAt error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if got := sliceSkipAt(out, tt.positions...); got != "abcdef" {
+					t.Errorf("sliceSkipAt(insertAt()) = %q, want %q", got, "abcdef")
+				}
+			}
+		})
+	}
+}
+
+func mustIndices(t *testing.T, obf *Obfuscator, s Salts) tokenIndices {
+	t.Helper()
+	idx, err := obf.parser.indicesFromSalts([5]int{s.Salt1, s.Salt2, s.Salt3, s.Salt4, s.Salt5})
+	if err != nil {
+		t.Fatalf("indicesFromSalts failed: %v", err)
+	}
+	return idx
+}
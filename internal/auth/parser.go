@@ -3,9 +3,7 @@ package auth
 import (
 	"context"
 	_ "embed"
-	"fmt"
 
-	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
 )
 
@@ -17,11 +15,17 @@ import (
 //go:embed css.wasm
 var cssWasm []byte
 
-// tokenParser wraps a WASM runtime to compute token character indices.
-// NEPSE obfuscates tokens by inserting characters at positions derived
-// from 5 salt values. This parser replicates the browser's decoding logic.
+// tokenIndexer computes the character positions NEPSE inserted into tokens.
+// tokenParser runs css.wasm; nativeParser is its pure-Go port.
+type tokenIndexer interface {
+	indicesFromSalts(s [5]int) (tokenIndices, error)
+	close() error
+}
+
+// tokenParser is one instance of css.wasm. Instances are not safe for
+// concurrent calls; [wasmPool] hands each caller its own.
 type tokenParser struct {
-	rt  wazero.Runtime
+	mod api.Module
 	cdx api.Function
 	rdx api.Function
 	bdx api.Function
@@ -29,40 +33,20 @@ type tokenParser struct {
 	mdx api.Function
 }
 
+// newTokenParser instantiates the embedded css.wasm from the shared compiled
+// module. The caller owns the instance and must close it.
 func newTokenParser() (*tokenParser, error) {
-	ctx := context.Background()
-	rt := wazero.NewRuntime(ctx)
-
-	compiled, err := rt.CompileModule(ctx, cssWasm)
-	if err != nil {
-		_ = rt.Close(ctx)
-		return nil, fmt.Errorf("compile wasm: %w", err)
-	}
-	mod, err := rt.InstantiateModule(ctx, compiled, wazero.NewModuleConfig())
+	pool, err := poolFor(cssWasm)
 	if err != nil {
-		_ = rt.Close(ctx)
-		return nil, fmt.Errorf("instantiate wasm: %w", err)
+		return nil, err
 	}
-
-	exports := []string{"cdx", "rdx", "bdx", "ndx", "mdx"}
-	funcs := make([]api.Function, len(exports))
-	for i, name := range exports {
-		f := mod.ExportedFunction(name)
-		if f == nil {
-			_ = rt.Close(ctx)
-			return nil, fmt.Errorf("export %q not found", name)
-		}
-		funcs[i] = f
-	}
-
-	return &tokenParser{
-		rt:  rt,
-		cdx: funcs[0], rdx: funcs[1], bdx: funcs[2], ndx: funcs[3], mdx: funcs[4],
-	}, nil
+	defer pool.close() // the embedded module's pool is never evicted
+	return pool.instantiate()
 }
 
+// close releases the instance's memory. The compiled module stays cached.
 func (p *tokenParser) close() error {
-	return p.rt.Close(context.Background())
+	return p.mod.Close(context.Background())
 }
 
 // call5 invokes a WASM function with 5 integer arguments.
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// pools caches one compiled module per distinct css.wasm, keyed by its
+// SHA-256, so every Manager in the process shares the compilation and the
+// idle instances. Entries are reference counted and closed when the last
+// holder releases them, except the embedded module's, which is kept for the
+// life of the process.
+var pools = struct {
+	mu     sync.Mutex
+	byHash map[[sha256.Size]byte]*wasmPool
+}{byHash: make(map[[sha256.Size]byte]*wasmPool)}
+
+// wasmPool is a compiled css.wasm and a free list of its instances. A WASM
+// instance cannot serve concurrent calls, so each indicesFromSalts call
+// checks one out and returns it afterwards. It implements tokenIndexer and
+// is safe for concurrent use.
+type wasmPool struct {
+	key    [sha256.Size]byte
+	refs   int  // holders that have not released the pool; guarded by pools.mu
+	pinned bool // the embedded module, never evicted
+
+	rt       wazero.Runtime
+	compiled wazero.CompiledModule
+	idle     chan *tokenParser
+}
+
+// poolFor returns the shared pool for module, compiling it on first use,
+// and takes a reference to it that the caller must drop with close.
+// Failed compilations are not cached.
+func poolFor(module []byte) (*wasmPool, error) {
+	key := sha256.Sum256(module)
+
+	pools.mu.Lock()
+	defer pools.mu.Unlock()
+	if p, ok := pools.byHash[key]; ok {
+		p.refs++
+		return p, nil
+	}
+
+	ctx := context.Background()
+	rt := wazero.NewRuntime(ctx)
+	compiled, err := rt.CompileModule(ctx, module)
+	if err != nil {
+		_ = rt.Close(ctx)
+		return nil, fmt.Errorf("compile wasm: %w", err)
+	}
+	p := &wasmPool{
+		key:      key,
+		refs:     1,
+		pinned:   key == sha256.Sum256(cssWasm),
+		rt:       rt,
+		compiled: compiled,
+		idle:     make(chan *tokenParser, runtime.GOMAXPROCS(0)),
+	}
+
+	// Instantiate once up front so a module missing an export fails here
+	// rather than on the first token refresh.
+	first, err := p.instantiate()
+	if err != nil {
+		_ = rt.Close(ctx)
+		return nil, err
+	}
+	p.idle <- first
+
+	pools.byHash[key] = p
+	return p, nil
+}
+
+// instantiate creates a new, unpooled instance of the compiled module.
+func (p *wasmPool) instantiate() (*tokenParser, error) {
+	ctx := context.Background()
+	// An empty name lets any number of instances coexist in one runtime.
+	mod, err := p.rt.InstantiateModule(ctx, p.compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return nil, fmt.Errorf("instantiate wasm: %w", err)
+	}
+
+	exports := []string{"cdx", "rdx", "bdx", "ndx", "mdx"}
+	funcs := make([]api.Function, len(exports))
+	for i, name := range exports {
+		f := mod.ExportedFunction(name)
+		if f == nil {
+			_ = mod.Close(ctx)
+			return nil, fmt.Errorf("export %q not found", name)
+		}
+		funcs[i] = f
+	}
+
+	return &tokenParser{
+		mod: mod,
+		cdx: funcs[0], rdx: funcs[1], bdx: funcs[2], ndx: funcs[3], mdx: funcs[4],
+	}, nil
+}
+
+// get checks out an idle instance, instantiating one if none is free.
+func (p *wasmPool) get() (*tokenParser, error) {
+	select {
+	case tp := <-p.idle:
+		return tp, nil
+	default:
+		return p.instantiate()
+	}
+}
+
+// put returns an instance to the pool, closing it if the pool is full.
+func (p *wasmPool) put(tp *tokenParser) {
+	select {
+	case p.idle <- tp:
+	default:
+		_ = tp.close()
+	}
+}
+
+func (p *wasmPool) indicesFromSalts(s [5]int) (tokenIndices, error) {
+	tp, err := p.get()
+	if err != nil {
+		return tokenIndices{}, err
+	}
+	idx, err := tp.indicesFromSalts(s)
+	if err != nil {
+		// A trapped instance may be left in a bad state; don't reuse it.
+		_ = tp.close()
+		return tokenIndices{}, err
+	}
+	p.put(tp)
+	return idx, nil
+}
+
+// close drops the caller's reference. The last one closes the runtime and
+// evicts the pool, unless it holds the embedded module.
+func (p *wasmPool) close() error {
+	pools.mu.Lock()
+	defer pools.mu.Unlock()
+	p.refs--
+	if p.refs > 0 || p.pinned {
+		return nil
+	}
+	delete(pools.byHash, p.key)
+	return p.rt.Close(context.Background())
+}
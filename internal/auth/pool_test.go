@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPoolFor_SharedAcrossManagers(t *testing.T) {
+	a, err := NewManager(&mockNepseHTTP{})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer a.Close()
+
+	b, err := NewManager(&mockNepseHTTP{}, WithWASMModule(append([]byte(nil), cssWasm...)))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer b.Close()
+
+	if _, ok := a.parser.(*wasmPool); !ok {
+		t.Fatalf("expected *wasmPool, got %T", a.parser)
+	}
+	if a.parser != b.parser {
+		t.Error("managers with identical modules should share one pool")
+	}
+}
+
+func TestPoolFor_ReleasesReplacementModules(t *testing.T) {
+	// A trailing custom section changes the hash but not the module.
+	module := append(append([]byte(nil), cssWasm...), 0, 6, 4, 't', 'e', 's', 't', 0)
+	cached := func() bool {
+		pools.mu.Lock()
+		defer pools.mu.Unlock()
+		_, ok := pools.byHash[sha256.Sum256(module)]
+		return ok
+	}
+
+	a, err := NewManager(&mockNepseHTTP{}, WithWASMModule(module))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	b, err := NewManager(&mockNepseHTTP{}, WithWASMModule(module))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if a.parser != b.parser {
+		t.Fatal("managers with identical modules should share one pool")
+	}
+
+	a.Close()
+	a.Close() // a second Close must not drop b's reference
+	if !cached() {
+		t.Fatal("pool evicted while a manager still uses it")
+	}
+	if _, err := b.parser.indicesFromSalts([5]int{1, 2, 3, 4, 5}); err != nil {
+		t.Fatalf("indicesFromSalts after the other manager closed: %v", err)
+	}
+	b.Close()
+	if cached() {
+		t.Error("pool still cached after its last manager closed")
+	}
+
+	embedded, err := NewManager(&mockNepseHTTP{})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	embedded.Close()
+	pools.mu.Lock()
+	_, ok := pools.byHash[sha256.Sum256(cssWasm)]
+	pools.mu.Unlock()
+	if !ok {
+		t.Error("embedded module's pool should never be evicted")
+	}
+}
+
+func TestWasmPool_ConcurrentIndices(t *testing.T) {
+	pool, err := poolFor(cssWasm)
+	if err != nil {
+		t.Fatalf("poolFor failed: %v", err)
+	}
+	defer pool.close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for g := range 64 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				s := [5]int{g*1000 + i, i*37 - g, g, i, g + i}
+				got, err := pool.indicesFromSalts(s)
+				if err != nil {
+					errs <- err
+					return
+				}
+				want, _ := nativeParser{}.indicesFromSalts(s)
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					errs <- fmt.Errorf("indicesFromSalts(%v) = %v, want %v", s, got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestManager_ConcurrentForceUpdateAcrossManagers runs many tenants, each with
+// its own salts and tokens, forcing refreshes concurrently through the shared
+// pool. Run with -race.
+func TestManager_ConcurrentForceUpdateAcrossManagers(t *testing.T) {
+	obf, err := NewObfuscator()
+	if err != nil {
+		t.Fatalf("NewObfuscator failed: %v", err)
+	}
+	defer obf.Close()
+
+	const tenants = 20
+	managers := make([]*Manager, tenants)
+	want := make([]string, tenants)
+	for i := range managers {
+		want[i] = fmt.Sprintf("tenant%02d", i) + strings.Repeat("x", 150)
+
+		var resp TokenResponse
+		for seed := i * 1000; ; seed++ {
+			salts := Salts{Salt1: seed * 7, Salt2: seed * 13, Salt3: seed, Salt4: seed * 3, Salt5: seed * 5}
+			resp, err = obf.Obfuscate(salts, want[i], want[i])
+			if err == nil {
+				break
+			}
+			if !errors.Is(err, ErrUnusableSalts) {
+				t.Fatalf("Obfuscate failed: %v", err)
+			}
+		}
+
+		mock := &mockNepseHTTP{tokenFunc: func(ctx context.Context) (*TokenResponse, error) {
+			r := resp
+			r.ServerTime = time.Now().UnixMilli()
+			return &r, nil
+		}}
+		managers[i], err = NewManager(mock)
+		if err != nil {
+			t.Fatalf("NewManager failed: %v", err)
+		}
+		defer managers[i].Close()
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, tenants*10)
+	for i, m := range managers {
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := m.ForceUpdate(ctx); err != nil {
+					errs <- err
+					return
+				}
+				got, err := m.AccessToken(ctx)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got != want[i] {
+					errs <- fmt.Errorf("tenant %d decoded %q, want %q", i, got, want[i])
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func BenchmarkWasmPool_IndicesFromSaltsParallel(b *testing.B) {
+	pool, err := poolFor(cssWasm)
+	if err != nil {
+		b.Fatalf("poolFor failed: %v", err)
+	}
+	salts := [5]int{1234, 5678, 9012, 3456, 7890}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := pool.indicesFromSalts(salts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
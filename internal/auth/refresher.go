@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// refreshTiming controls the background refresher. Tokens are renewed lead
+// before the TTL, less up to jitter so that many clients started together
+// spread their requests. Failures back off exponentially from backoff.
+type refreshTiming struct {
+	lead       time.Duration
+	jitter     time.Duration
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+var defaultRefreshTiming = refreshTiming{
+	lead:       5 * time.Second,
+	jitter:     5 * time.Second,
+	backoff:    time.Second,
+	maxBackoff: 30 * time.Second,
+}
+
+// RefreshStats counts token refreshes, lazy and background alike.
+type RefreshStats struct {
+	Successes uint64
+	Failures  uint64
+	Proves    uint64        // Successes that needed a full /api/authenticate/prove
+	Renewals  uint64        // Successes that renewed with the refresh token
+	TokenAge  time.Duration // Time since NEPSE issued the current token; zero before the first
+}
+
+// RefreshEvent describes one token refresh attempt.
+type RefreshEvent struct {
+	Background bool          // Started by the background refresher rather than a request
+	Err        error         // Nil on success
+	Latency    time.Duration // Time spent fetching and decoding the token
+	Stats      RefreshStats  // Counters including this attempt
+}
+
+// StartBackgroundRefresh renews the token shortly before it expires, so
+// requests never wait on /api/authenticate/prove. The first token is fetched
+// immediately. Failures are retried with exponential backoff until [Manager.Close].
+// Calling it again, or after Close, does nothing.
+func (m *Manager) StartBackgroundRefresh() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.refresher != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.refresher = &refresher{cancel: cancel, done: make(chan struct{})}
+	go m.refresher.loop(ctx, m, m.timing)
+}
+
+// WithRefreshHook calls fn after every token refresh attempt. fn runs on the
+// refreshing goroutine and should return quickly.
+func WithRefreshHook(fn func(RefreshEvent)) Option {
+	return func(m *Manager) {
+		m.hook = fn
+	}
+}
+
+// Stats returns the refresh counters and the current token's age.
+func (m *Manager) Stats() RefreshStats {
+	s := RefreshStats{
+		Successes: m.successes.Load(),
+		Failures:  m.failures.Load(),
+		Proves:    m.proves.Load(),
+		Renewals:  m.renewals.Load(),
+	}
+	m.mu.RLock()
+	if !m.tokenTS.IsZero() {
+		s.TokenAge = m.tokenAgeLocked()
+	}
+	m.mu.RUnlock()
+	return s
+}
+
+// refresher runs the background refresh loop.
+type refresher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// stop cancels any refresh in flight and waits for the loop to exit.
+func (r *refresher) stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.done
+}
+
+func (r *refresher) loop(ctx context.Context, m *Manager, t refreshTiming) {
+	defer close(r.done)
+
+	failures := 0
+	for {
+		timer := time.NewTimer(t.next(m, failures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// A 401, ForceUpdate, or lazy refresh may have renewed the token
+		// while the timer ran; wait for the new one to come due instead.
+		if !t.due(m) {
+			failures = 0
+			continue
+		}
+		if err := m.refresh(ctx, true); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			failures++
+			continue
+		}
+		failures = 0
+	}
+}
+
+// due reports whether the token is missing or close enough to expiry, by the
+// lead and the most jitter next can subtract, for the refresher to renew it.
+func (t refreshTiming) due(m *Manager) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tokenTS.IsZero() || m.tokenAgeLocked() >= m.maxUpdatePeriod-t.lead-t.jitter
+}
+
+// next returns how long to wait before the next refresh attempt.
+func (t refreshTiming) next(m *Manager, failures int) time.Duration {
+	if failures > 0 {
+		// Equal jitter: at least half the backoff, so a dead endpoint is
+		// never hammered.
+		d := min(t.backoff<<min(failures-1, 30), t.maxBackoff)
+		return d/2 + rand.N(d/2+1)
+	}
+
+	m.mu.RLock()
+	issued := !m.tokenTS.IsZero()
+	age := m.tokenAgeLocked()
+	m.mu.RUnlock()
+	if !issued {
+		return 0
+	}
+
+	wait := m.maxUpdatePeriod - t.lead - age
+	if t.jitter > 0 {
+		wait -= rand.N(t.jitter)
+	}
+	// A token that is already due, e.g. because NEPSE's clock lags ours, must
+	// not turn the loop into a busy retry.
+	return min(max(wait, t.backoff), m.maxUpdatePeriod)
+}
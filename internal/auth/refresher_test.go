@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fastRefresh shortens every background refresh interval for tests.
+func fastRefresh(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.maxUpdatePeriod = ttl
+		m.timing = refreshTiming{
+			lead:       ttl / 4,
+			jitter:     ttl / 10,
+			backoff:    5 * time.Millisecond,
+			maxBackoff: 20 * time.Millisecond,
+		}
+	}
+}
+
+// localTimeToken returns a token response without serverTime, so the token
+// is timestamped with the local clock.
+func localTimeToken(context.Context) (*TokenResponse, error) {
+	return &TokenResponse{
+		Salt1: 1234, Salt2: 5678, Salt3: 9012, Salt4: 3456, Salt5: 7890,
+		AccessToken: "testXtokenYwithZjunkAcharsB",
+	}, nil
+}
+
+func TestManager_BackgroundRefresh(t *testing.T) {
+	mock := &mockNepseHTTP{tokenFunc: localTimeToken}
+
+	var mu sync.Mutex
+	var events []RefreshEvent
+	hook := WithRefreshHook(func(e RefreshEvent) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	})
+
+	manager, err := NewManager(mock, fastRefresh(100*time.Millisecond), hook)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+	manager.StartBackgroundRefresh()
+
+	// The first token is fetched eagerly and renewed before every expiry, so
+	// a caller polling throughout never triggers a lazy fetch.
+	deadline := time.Now().Add(450 * time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if !manager.isValid() {
+			t.Fatal("token expired despite background refresh")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stats := manager.Stats()
+	if stats.Successes < 4 || stats.Failures != 0 {
+		t.Errorf("unexpected stats after ~4 TTLs: %+v", stats)
+	}
+	if stats.TokenAge <= 0 || stats.TokenAge >= 100*time.Millisecond {
+		t.Errorf("TokenAge = %v, want within TTL", stats.TokenAge)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, e := range events {
+		if !e.Background || e.Err != nil {
+			t.Errorf("unexpected event %+v", e)
+		}
+	}
+}
+
+func TestManager_BackgroundRefreshBackoff(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+	mock := &mockNepseHTTP{tokenFunc: func(ctx context.Context) (*TokenResponse, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls <= 3 {
+			return nil, errors.New("nepse down")
+		}
+		return localTimeToken(ctx)
+	}}
+
+	manager, err := NewManager(mock, fastRefresh(time.Minute))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+	manager.StartBackgroundRefresh()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !manager.isValid() {
+		if time.Now().After(deadline) {
+			t.Fatal("background refresher never recovered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if s := manager.Stats(); s.Failures != 3 || s.Successes != 1 {
+		t.Errorf("Stats() = %+v, want 3 failures then 1 success", s)
+	}
+}
+
+func TestManager_BackgroundRefreshSkipsRenewedToken(t *testing.T) {
+	var mu sync.Mutex
+	var background int
+	hook := WithRefreshHook(func(e RefreshEvent) {
+		if e.Background {
+			mu.Lock()
+			background++
+			mu.Unlock()
+		}
+	})
+
+	// The refresher's first timer fires 260-300ms after the eager fetch.
+	manager, err := NewManager(&mockNepseHTTP{tokenFunc: localTimeToken}, fastRefresh(400*time.Millisecond), hook)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+	manager.StartBackgroundRefresh()
+
+	time.Sleep(150 * time.Millisecond)
+	if err := manager.ForceUpdate(context.Background()); err != nil {
+		t.Fatalf("ForceUpdate failed: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if background != 1 {
+		t.Errorf("background fetches = %d, want only the eager one after ForceUpdate renewed the token", background)
+	}
+}
+
+func TestManager_CloseStopsBackgroundRefresh(t *testing.T) {
+	started := make(chan struct{})
+	mock := &mockNepseHTTP{tokenFunc: func(ctx context.Context) (*TokenResponse, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+
+	manager, err := NewManager(mock)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	manager.StartBackgroundRefresh()
+	manager.StartBackgroundRefresh() // no-op while running
+	<-started
+
+	// Close must cancel the in-flight fetch and wait for the loop to exit.
+	done := make(chan struct{})
+	go func() {
+		manager.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop the background refresher")
+	}
+	if err := manager.Close(); err != nil {
+		t.Errorf("second Close failed: %v", err)
+	}
+
+	manager.StartBackgroundRefresh() // no-op after Close
+	calls := mock.callCount.Load()
+	time.Sleep(20 * time.Millisecond)
+	if mock.callCount.Load() != calls {
+		t.Error("token fetched after Close")
+	}
+}
+
+func TestManager_StatsCountLazyRefreshes(t *testing.T) {
+	var events []RefreshEvent
+	mock := &mockNepseHTTP{}
+	manager, err := NewManager(mock, WithRefreshHook(func(e RefreshEvent) { events = append(events, e) }))
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+
+	if s := manager.Stats(); s != (RefreshStats{}) {
+		t.Errorf("Stats() before first token = %+v, want zero", s)
+	}
+	if _, err := manager.AccessToken(context.Background()); err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	if s := manager.Stats(); s.Successes != 1 || s.Failures != 0 {
+		t.Errorf("Stats() = %+v, want 1 success", s)
+	}
+	if len(events) != 1 || events[0].Background || events[0].Stats.Successes != 1 {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// renewingHTTP issues obfuscated token pairs and renews them, recording the
+// refresh tokens it is offered.
+type renewingHTTP struct {
+	obf      *Obfuscator
+	renewErr error
+	issued   atomic.Int32
+	proves   atomic.Int32
+	offered  []string
+}
+
+func (h *renewingHTTP) issue() (*TokenResponse, error) {
+	n := h.issued.Add(1)
+	access := fmt.Sprintf("access%02d", n) + strings.Repeat("a", 150)
+	refresh := fmt.Sprintf("refresh%02d", n) + strings.Repeat("r", 150)
+	for seed := 1; ; seed++ {
+		salts := Salts{Salt1: seed * 7, Salt2: seed * 13, Salt3: seed, Salt4: seed * 3, Salt5: seed * 5}
+		resp, err := h.obf.Obfuscate(salts, access, refresh)
+		if errors.Is(err, ErrUnusableSalts) {
+			continue
+		}
+		return &resp, err
+	}
+}
+
+func (h *renewingHTTP) Token(context.Context) (*TokenResponse, error) {
+	h.proves.Add(1)
+	return h.issue()
+}
+
+func (h *renewingHTTP) RenewToken(_ context.Context, refreshToken string) (*TokenResponse, error) {
+	h.offered = append(h.offered, refreshToken)
+	if h.renewErr != nil {
+		return nil, h.renewErr
+	}
+	return h.issue()
+}
+
+func newRenewingManager(t *testing.T, renewErr error) (*Manager, *renewingHTTP) {
+	t.Helper()
+	obf, err := NewObfuscator()
+	if err != nil {
+		t.Fatalf("NewObfuscator failed: %v", err)
+	}
+	t.Cleanup(func() { obf.Close() })
+
+	h := &renewingHTTP{obf: obf, renewErr: renewErr}
+	manager, err := NewManager(h)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+	return manager, h
+}
+
+func TestManager_RenewsWithRefreshToken(t *testing.T) {
+	manager, h := newRenewingManager(t, nil)
+	ctx := context.Background()
+
+	if _, err := manager.AccessToken(ctx); err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	for range 2 {
+		if err := manager.ForceUpdate(ctx); err != nil {
+			t.Fatalf("ForceUpdate failed: %v", err)
+		}
+	}
+
+	want := []string{"refresh01" + strings.Repeat("r", 150), "refresh02" + strings.Repeat("r", 150)}
+	if fmt.Sprint(h.offered) != fmt.Sprint(want) {
+		t.Errorf("offered refresh tokens %q, want decoded %q", h.offered, want)
+	}
+	if got, _ := manager.AccessToken(ctx); got != "access03"+strings.Repeat("a", 150) {
+		t.Errorf("AccessToken() = %q after renewal", got)
+	}
+	if s := manager.Stats(); h.proves.Load() != 1 || s.Proves != 1 || s.Renewals != 2 {
+		t.Errorf("expected 1 prove and 2 renewals, got %d proves, stats %+v", h.proves.Load(), s)
+	}
+}
+
+func TestManager_RejectedRefreshFallsBackToProve(t *testing.T) {
+	manager, h := newRenewingManager(t, fmt.Errorf("%w: status 401", ErrRefreshRejected))
+	ctx := context.Background()
+
+	if _, err := manager.AccessToken(ctx); err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	if err := manager.ForceUpdate(ctx); err != nil {
+		t.Fatalf("ForceUpdate failed: %v", err)
+	}
+	if h.proves.Load() != 2 || len(h.offered) != 1 {
+		t.Errorf("expected renewal then prove, got %d proves and %d renewals", h.proves.Load(), len(h.offered))
+	}
+	if s := manager.Stats(); s.Proves != 2 || s.Renewals != 0 || s.Failures != 0 {
+		t.Errorf("Stats() = %+v", s)
+	}
+}
+
+func TestManager_FailedRenewalFallsBackToProve(t *testing.T) {
+	for name, renewErr := range map[string]error{
+		"transport error": errors.New("connection reset"),
+		"undecodable":     errors.New("failed to decode token response"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			manager, h := newRenewingManager(t, renewErr)
+			ctx := context.Background()
+
+			if _, err := manager.AccessToken(ctx); err != nil {
+				t.Fatalf("AccessToken failed: %v", err)
+			}
+			if err := manager.ForceUpdate(ctx); err != nil {
+				t.Fatalf("ForceUpdate failed: %v", err)
+			}
+			if h.proves.Load() != 2 || len(h.offered) != 1 {
+				t.Errorf("expected renewal then prove, got %d proves and %d renewals", h.proves.Load(), len(h.offered))
+			}
+
+			// The failed refresh token was dropped; the prove's new one is tried next.
+			if err := manager.ForceUpdate(ctx); err != nil {
+				t.Fatalf("ForceUpdate failed: %v", err)
+			}
+			if len(h.offered) != 2 || h.offered[0] == h.offered[1] {
+				t.Errorf("offered refresh tokens %q, want the failed one not retried", h.offered)
+			}
+		})
+	}
+}
+
+func TestManager_UnsupportedRefreshDisablesRenewal(t *testing.T) {
+	manager, h := newRenewingManager(t, fmt.Errorf("%w: status 404", ErrRefreshUnsupported))
+	ctx := context.Background()
+
+	if _, err := manager.AccessToken(ctx); err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+	for range 3 {
+		if err := manager.ForceUpdate(ctx); err != nil {
+			t.Fatalf("ForceUpdate failed: %v", err)
+		}
+	}
+	if len(h.offered) != 1 || h.proves.Load() != 4 {
+		t.Errorf("expected one renewal attempt then proves only, got %d renewals and %d proves", len(h.offered), h.proves.Load())
+	}
+}
+
+func TestManager_CanceledRenewalIsReturned(t *testing.T) {
+	manager, h := newRenewingManager(t, context.Canceled)
+	if _, err := manager.AccessToken(context.Background()); err != nil {
+		t.Fatalf("AccessToken failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := manager.ForceUpdate(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ForceUpdate err = %v, want context.Canceled", err)
+	}
+	if h.proves.Load() != 1 {
+		t.Errorf("a canceled renewal must not prove, got %d proves", h.proves.Load())
+	}
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+)
+
+// TokenVector is a known set of salts and the character positions the token
+// parser must compute for them. Vectors for a replacement css.wasm can be
+// captured from NEPSE's website in a browser.
+type TokenVector struct {
+	Salts   [5]int
+	Access  []int
+	Refresh []int
+}
+
+// DefaultTokenVectors returns vectors that hold for the embedded css.wasm.
+func DefaultTokenVectors() []TokenVector {
+	return []TokenVector{
+		{Salts: [5]int{1234, 5678, 9012, 3456, 7890}, Access: []int{28, 51, 79, 101, 122}, Refresh: []int{27, 42, 70, 96, 117}},
+		{Salts: [5]int{0, 0, 0, 0, 0}, Access: []int{27, 37, 65, 93, 115}, Refresh: []int{27, 37, 65, 93, 115}},
+		{Salts: [5]int{-50, 100, 0, 999, -1}, Access: []int{30, 41, 69, 96, 119}, Refresh: []int{22, 27, 55, 83, 110}},
+		{Salts: [5]int{38204, 71563, 12877, 96021, 45310}, Access: []int{26, 47, 75, 98, 119}, Refresh: []int{28, 40, 68, 94, 118}},
+	}
+}
+
+// Verify runs every vector through the manager's token parser and reports
+// the first that does not hold.
+func (m *Manager) Verify(vectors []TokenVector) error {
+	for i, v := range vectors {
+		idx, err := m.parser.indicesFromSalts(v.Salts)
+		if err != nil {
+			return fmt.Errorf("token vector %d: %w", i, err)
+		}
+		if !slices.Equal(idx.access, v.Access) || !slices.Equal(idx.refresh, v.Refresh) {
+			return fmt.Errorf("token vector %d: salts %v gave access %v refresh %v, want access %v refresh %v",
+				i, v.Salts, idx.access, idx.refresh, v.Access, v.Refresh)
+		}
+	}
+	return nil
+}
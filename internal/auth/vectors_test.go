@@ -0,0 +1,46 @@
+package auth
+
+import "testing"
+
+func TestManager_VerifyDefaultVectors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"embedded", nil},
+		{"module", []Option{WithWASMModule(cssWasm)}},
+		{"native", []Option{WithNativeParser()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			manager, err := NewManager(&mockNepseHTTP{}, tc.opts...)
+			if err != nil {
+				t.Fatalf("NewManager failed: %v", err)
+			}
+			defer manager.Close()
+
+			if err := manager.Verify(DefaultTokenVectors()); err != nil {
+				t.Errorf("Verify failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestManager_VerifyMismatch(t *testing.T) {
+	manager, err := NewManager(&mockNepseHTTP{})
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer manager.Close()
+
+	vectors := DefaultTokenVectors()
+	vectors[1].Refresh = []int{1, 2, 3, 4, 5}
+	if err := manager.Verify(vectors); err == nil {
+		t.Error("expected mismatched vector to fail")
+	}
+}
+
+func TestWithWASMModule_Invalid(t *testing.T) {
+	if _, err := NewManager(&mockNepseHTTP{}, WithWASMModule([]byte("not wasm"))); err == nil {
+		t.Error("expected invalid module to fail")
+	}
+}
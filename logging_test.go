@@ -0,0 +1,120 @@
+package nepse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for use by concurrent log writers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestClient_StructuredLogging(t *testing.T) {
+	var apiCalls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tokenResponse())
+		case "/api/nots/nepse-data/market-open":
+			if apiCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(MarketStatus{IsOpen: "OPEN"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var logs syncBuffer
+	client, err := NewClient(&Options{
+		BaseURL:     server.URL,
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  0,
+		Logger:      slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		Config: &Config{
+			BaseURL:   server.URL,
+			Endpoints: DefaultEndpoints(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if _, err := client.MarketStatus(ctx); err != nil {
+		t.Fatalf("MarketStatus failed: %v", err)
+	}
+	token, err := client.DebugDecodedToken(ctx)
+	if err != nil {
+		t.Fatalf("DebugDecodedToken failed: %v", err)
+	}
+
+	var sawUnauthorized, sawRefresh, sawTokenRefreshed bool
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		switch rec["msg"] {
+		case "nepse request":
+			if rec["endpoint"] == "MarketOpen" && rec["status"] == float64(http.StatusUnauthorized) {
+				sawUnauthorized = true
+				if rec["attempt"] != float64(1) {
+					t.Errorf("expected attempt 1, got %v", rec["attempt"])
+				}
+				if _, ok := rec["latency"]; !ok {
+					t.Error("expected latency attribute")
+				}
+			}
+		case "nepse access token rejected, refreshing":
+			sawRefresh = true
+		case "nepse token refreshed":
+			sawTokenRefreshed = true
+			if rec["token"] != "[REDACTED]" {
+				t.Errorf("expected redacted token, got %v", rec["token"])
+			}
+		}
+	}
+
+	if !sawUnauthorized {
+		t.Error("expected a request log with status 401 for MarketOpen")
+	}
+	if !sawRefresh {
+		t.Error("expected a 401 refresh log")
+	}
+	if !sawTokenRefreshed {
+		t.Error("expected a token refresh log from the auth manager")
+	}
+	if strings.Contains(logs.String(), token) || strings.Contains(logs.String(), tokenResponse().AccessToken) {
+		t.Error("access token leaked into logs")
+	}
+}
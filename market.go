@@ -3,9 +3,16 @@ package nepse
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // Index IDs used by NEPSE API.
@@ -18,6 +25,8 @@ const (
 
 // MarketSummary returns aggregate market statistics including turnover, volume, and capitalization.
 func (c *Client) MarketSummary(ctx context.Context) (*MarketSummary, error) {
+	ctx = withOperation(ctx, "MarketSummary")
+
 	var rawItems []MarketSummaryItem
 	if err := c.apiRequest(ctx, c.config.Endpoints.MarketSummary, &rawItems); err != nil {
 		return nil, err
@@ -46,19 +55,27 @@ func (c *Client) MarketSummary(ctx context.Context) (*MarketSummary, error) {
 
 // MarketStatus returns whether the market is currently open or closed.
 func (c *Client) MarketStatus(ctx context.Context) (*MarketStatus, error) {
+	ctx = withOperation(ctx, "MarketStatus")
+
 	var status MarketStatus
 	if err := c.apiRequest(ctx, c.config.Endpoints.MarketOpen, &status); err != nil {
 		return nil, err
 	}
+	c.payloadIDs.observeStatus(status.ID)
 	return &status, nil
 }
 
 // NepseIndex returns the main NEPSE index with current value, change, and 52-week range.
 func (c *Client) NepseIndex(ctx context.Context) (*NepseIndex, error) {
+	ctx = withOperation(ctx, "NepseIndex")
+
 	var rawIndices []NepseIndexRaw
 	if err := c.apiRequest(ctx, c.config.Endpoints.NepseIndex, &rawIndices); err != nil {
 		return nil, err
 	}
+	if len(rawIndices) == 0 {
+		return nil, NewEmptyResponseError("NEPSE index")
+	}
 
 	for i := range rawIndices {
 		if rawIndices[i].ID == nepseIndexID {
@@ -82,8 +99,11 @@ func (c *Client) NepseIndex(ctx context.Context) (*NepseIndex, error) {
 
 // SubIndices returns other main indices (Sensitive, Float, Sensitive Float)
 // excluding the main NEPSE index.
+// Returns [ErrEmptyResponse] if NEPSE returns no indices.
 // Note: Sector sub-indices are only available through graph endpoints.
 func (c *Client) SubIndices(ctx context.Context) ([]SubIndex, error) {
+	ctx = withOperation(ctx, "SubIndices")
+
 	var rawIndices []NepseIndexRaw
 	if err := c.apiRequest(ctx, c.config.Endpoints.NepseIndex, &rawIndices); err != nil {
 		return nil, err
@@ -96,19 +116,42 @@ func (c *Client) SubIndices(ctx context.Context) ([]SubIndex, error) {
 			subIndices = append(subIndices, SubIndex(rawIndices[i]))
 		}
 	}
+	if len(subIndices) == 0 {
+		return nil, NewEmptyResponseError("sub-indices")
+	}
 
 	return subIndices, nil
 }
 
 // LiveMarket returns real-time price and volume data for all actively traded securities.
+// Returns [ErrMarketClosed] if there is no live data because the market is closed,
+// or [ErrEmptyResponse] if there is none while it is open.
 func (c *Client) LiveMarket(ctx context.Context) ([]LiveMarketEntry, error) {
+	ctx = withOperation(ctx, "LiveMarket")
+
 	var liveMarket []LiveMarketEntry
 	if err := c.apiRequest(ctx, c.config.Endpoints.LiveMarket, &liveMarket); err != nil {
 		return nil, err
 	}
+	if len(liveMarket) == 0 {
+		return nil, c.emptyMarketError(ctx, "live market")
+	}
 	return liveMarket, nil
 }
 
+// emptyMarketError explains an empty response for market-hours data:
+// [ErrMarketClosed] if the market is closed, [ErrEmptyResponse] otherwise.
+func (c *Client) emptyMarketError(ctx context.Context, what string) error {
+	status, err := c.MarketStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if !status.IsMarketOpen() {
+		return NewMarketClosedError(status.AsOf.String())
+	}
+	return NewEmptyResponseError(what)
+}
+
 // SupplyDemandData represents the combined supply and demand response.
 type SupplyDemandData struct {
 	SupplyList []SupplyDemandItem `json:"supplyList"`
@@ -126,6 +169,8 @@ type SupplyDemandItem struct {
 
 // SupplyDemand returns aggregate supply and demand data.
 func (c *Client) SupplyDemand(ctx context.Context) (*SupplyDemandData, error) {
+	ctx = withOperation(ctx, "SupplyDemand")
+
 	var data SupplyDemandData
 	if err := c.apiRequest(ctx, c.config.Endpoints.SupplyDemand, &data); err != nil {
 		return nil, err
@@ -135,6 +180,8 @@ func (c *Client) SupplyDemand(ctx context.Context) (*SupplyDemandData, error) {
 
 // TopGainers returns securities with the highest percentage gains for the trading day.
 func (c *Client) TopGainers(ctx context.Context) ([]TopGainerLoserEntry, error) {
+	ctx = withOperation(ctx, "TopGainers")
+
 	var topGainers []TopGainerLoserEntry
 	if err := c.apiRequest(ctx, c.config.Endpoints.TopGainers, &topGainers); err != nil {
 		return nil, err
@@ -144,6 +191,8 @@ func (c *Client) TopGainers(ctx context.Context) ([]TopGainerLoserEntry, error)
 
 // TopLosers returns securities with the highest percentage losses for the trading day.
 func (c *Client) TopLosers(ctx context.Context) ([]TopGainerLoserEntry, error) {
+	ctx = withOperation(ctx, "TopLosers")
+
 	var topLosers []TopGainerLoserEntry
 	if err := c.apiRequest(ctx, c.config.Endpoints.TopLosers, &topLosers); err != nil {
 		return nil, err
@@ -153,6 +202,8 @@ func (c *Client) TopLosers(ctx context.Context) ([]TopGainerLoserEntry, error) {
 
 // TopTenTrade returns the ten securities with the highest traded share volume.
 func (c *Client) TopTenTrade(ctx context.Context) ([]TopTradeEntry, error) {
+	ctx = withOperation(ctx, "TopTenTrade")
+
 	var topTrade []TopTradeEntry
 	if err := c.apiRequest(ctx, c.config.Endpoints.TopTrade, &topTrade); err != nil {
 		return nil, err
@@ -162,6 +213,8 @@ func (c *Client) TopTenTrade(ctx context.Context) ([]TopTradeEntry, error) {
 
 // TopTenTransaction returns the ten securities with the most transactions.
 func (c *Client) TopTenTransaction(ctx context.Context) ([]TopTransactionEntry, error) {
+	ctx = withOperation(ctx, "TopTenTransaction")
+
 	var topTransaction []TopTransactionEntry
 	if err := c.apiRequest(ctx, c.config.Endpoints.TopTransaction, &topTransaction); err != nil {
 		return nil, err
@@ -171,6 +224,8 @@ func (c *Client) TopTenTransaction(ctx context.Context) ([]TopTransactionEntry,
 
 // TopTenTurnover returns the ten securities with the highest trading turnover (value).
 func (c *Client) TopTenTurnover(ctx context.Context) ([]TopTurnoverEntry, error) {
+	ctx = withOperation(ctx, "TopTenTurnover")
+
 	var topTurnover []TopTurnoverEntry
 	if err := c.apiRequest(ctx, c.config.Endpoints.TopTurnover, &topTurnover); err != nil {
 		return nil, err
@@ -179,17 +234,19 @@ func (c *Client) TopTenTurnover(ctx context.Context) ([]TopTurnoverEntry, error)
 }
 
 // TodaysPrices returns price data for all securities on a given business date.
-// If businessDate is empty, returns data for the current trading day.
+// If businessDate is the zero Date, returns data for the current trading day.
 //
-// Note: This endpoint may return empty results. NEPSE's web interface uses a POST request
-// that requires additional authentication not currently supported by this library.
-// For current prices, consider using [Client.TopGainers], [Client.TopLosers], or
+// Note: This endpoint may return empty results, reported as [ErrEmptyResponse]. NEPSE's web
+// interface uses a POST request that requires additional authentication not currently
+// supported by this library. For current prices, consider using [Client.TopGainers], [Client.TopLosers], or
 // [Client.Company] which return LTP (last traded price) data.
-func (c *Client) TodaysPrices(ctx context.Context, businessDate string) ([]TodayPrice, error) {
+func (c *Client) TodaysPrices(ctx context.Context, businessDate Date) ([]TodayPrice, error) {
+	ctx = withOperation(ctx, "TodaysPrices")
+
 	endpoint := c.config.Endpoints.TodaysPrice
-	if businessDate != "" {
+	if !businessDate.IsZero() {
 		params := url.Values{}
-		params.Set("businessDate", businessDate)
+		params.Set("businessDate", businessDate.String())
 		params.Set("size", "500")
 		endpoint += "?" + params.Encode()
 	}
@@ -198,38 +255,118 @@ func (c *Client) TodaysPrices(ctx context.Context, businessDate string) ([]Today
 	if err := c.apiRequest(ctx, endpoint, &todayPrices); err != nil {
 		return nil, err
 	}
+	if len(todayPrices) == 0 {
+		return nil, NewEmptyResponseError("today's prices")
+	}
 	return todayPrices, nil
 }
 
-// PriceHistory returns historical OHLCV data for a security within a date range.
-func (c *Client) PriceHistory(ctx context.Context, securityID int32, startDate, endDate string) ([]PriceHistory, error) {
-	params := url.Values{}
-	params.Set("size", "500")
-	params.Set("startDate", startDate)
-	params.Set("endDate", endDate)
-	endpoint := fmt.Sprintf("%s/%d?%s", c.config.Endpoints.CompanyPriceHistory, securityID, params.Encode())
+// PriceHistory returns historical OHLCV data for a security within r, sorted
+// by BusinessDate. When both ends of r are set, long ranges are fetched as
+// described at [Client.PriceHistoryRange]; otherwise NEPSE picks the
+// missing end and the result is read in a single range.
+func (c *Client) PriceHistory(ctx context.Context, securityID int32, r DateRange) ([]PriceHistory, error) {
+	ctx = withOperation(ctx, "PriceHistory")
 
-	var response struct {
-		Content []PriceHistory `json:"content"`
+	if !r.Bounded() {
+		return c.priceHistoryChunk(ctx, securityID, r, nil)
 	}
+	return c.PriceHistoryRange(ctx, securityID, r, nil)
+}
+
+// PriceHistoryBySymbol returns historical OHLCV data for a security by symbol.
+func (c *Client) PriceHistoryBySymbol(ctx context.Context, symbol string, r DateRange) ([]PriceHistory, error) {
+	ctx = withOperation(ctx, "PriceHistoryBySymbol")
 
-	if err := c.apiRequest(ctx, endpoint, &response); err != nil {
+	security, err := c.findSecurityBySymbol(ctx, symbol)
+	if err != nil {
 		return nil, err
 	}
-	return response.Content, nil
+	return c.PriceHistory(ctx, security.ID, r)
 }
 
-// PriceHistoryBySymbol returns historical OHLCV data for a security by symbol.
-func (c *Client) PriceHistoryBySymbol(ctx context.Context, symbol string, startDate, endDate string) ([]PriceHistory, error) {
-	security, err := c.findSecurityBySymbol(ctx, symbol)
+// PriceHistoryRange returns historical OHLCV data for a security between
+// r.From and r.To, inclusive, sorted by BusinessDate with duplicates
+// removed. The range is split into chunks of opts.ChunkDays, fetched at most
+// opts.Concurrency at a time, and every page of each chunk is read.
+func (c *Client) PriceHistoryRange(ctx context.Context, securityID int32, r DateRange, opts *PriceHistoryOptions) ([]PriceHistory, error) {
+	ctx = withOperation(ctx, "PriceHistoryRange")
+
+	if !r.Bounded() {
+		return nil, NewInvalidClientRequestError("price history range needs both a start and an end date")
+	}
+	chunks := dateChunks(r, opts.chunkDays())
+	if len(chunks) == 0 {
+		return nil, NewInvalidClientRequestError("price history start date is after end date")
+	}
+
+	results := make([][]PriceHistory, len(chunks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.concurrency())
+	for i, chunk := range chunks {
+		g.Go(func() error {
+			rows, err := c.priceHistoryChunk(gctx, securityID, chunk, opts)
+			results[i] = rows
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[Date]bool)
+	all := []PriceHistory{}
+	for _, rows := range results {
+		for _, row := range rows {
+			if !seen[row.BusinessDate] {
+				seen[row.BusinessDate] = true
+				all = append(all, row)
+			}
+		}
+	}
+	slices.SortFunc(all, func(a, b PriceHistory) int {
+		return a.BusinessDate.Compare(b.BusinessDate)
+	})
+	return all, nil
+}
+
+// priceHistoryChunk reads every page of one date range, sorted by BusinessDate.
+// A zero end of r is left out of the request.
+func (c *Client) priceHistoryChunk(ctx context.Context, securityID int32, r DateRange, opts *PriceHistoryOptions) ([]PriceHistory, error) {
+	pages := &PageOptions{PageSize: opts.pageSize()}
+	rows, err := collect(paginate(ctx, pages, func(ctx context.Context, page, size int) (*PaginatedResponse[PriceHistory], error) {
+		params := url.Values{}
+		params.Set("size", strconv.Itoa(size))
+		if !r.From.IsZero() {
+			params.Set("startDate", r.From.String())
+		}
+		if !r.To.IsZero() {
+			params.Set("endDate", r.To.String())
+		}
+		if page > 0 {
+			params.Set("page", strconv.Itoa(page))
+		}
+		endpoint := fmt.Sprintf("%s/%d?%s", c.config.Endpoints.CompanyPriceHistory, securityID, params.Encode())
+
+		var resp PaginatedResponse[PriceHistory]
+		if err := c.apiRequest(ctx, endpoint, &resp); err != nil {
+			return nil, err
+		}
+		return &resp, nil
+	}))
 	if err != nil {
 		return nil, err
 	}
-	return c.PriceHistory(ctx, security.ID, startDate, endDate)
+	slices.SortFunc(rows, func(a, b PriceHistory) int {
+		return a.BusinessDate.Compare(b.BusinessDate)
+	})
+	return rows, nil
 }
 
 // MarketDepth returns the order book (bid/ask levels) for a security.
 func (c *Client) MarketDepth(ctx context.Context, securityID int32) (*MarketDepth, error) {
+	ctx = withOperation(ctx, "MarketDepth")
+
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.MarketDepth, securityID)
 
 	var raw MarketDepthRaw
@@ -247,6 +384,8 @@ func (c *Client) MarketDepth(ctx context.Context, securityID int32) (*MarketDept
 
 // MarketDepthBySymbol returns the order book for a security by ticker symbol.
 func (c *Client) MarketDepthBySymbol(ctx context.Context, symbol string) (*MarketDepth, error) {
+	ctx = withOperation(ctx, "MarketDepthBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
@@ -256,6 +395,8 @@ func (c *Client) MarketDepthBySymbol(ctx context.Context, symbol string) (*Marke
 
 // Securities returns all tradable securities on the exchange.
 func (c *Client) Securities(ctx context.Context) ([]Security, error) {
+	ctx = withOperation(ctx, "Securities")
+
 	var securities []Security
 	if err := c.apiRequest(ctx, c.config.Endpoints.SecurityList, &securities); err != nil {
 		return nil, err
@@ -265,6 +406,8 @@ func (c *Client) Securities(ctx context.Context) ([]Security, error) {
 
 // Companies returns all listed companies on the exchange.
 func (c *Client) Companies(ctx context.Context) ([]Company, error) {
+	ctx = withOperation(ctx, "Companies")
+
 	var companies []Company
 	if err := c.apiRequest(ctx, c.config.Endpoints.CompanyList, &companies); err != nil {
 		return nil, err
@@ -274,6 +417,8 @@ func (c *Client) Companies(ctx context.Context) ([]Company, error) {
 
 // Company returns comprehensive information including price data for a security.
 func (c *Client) Company(ctx context.Context, securityID int32) (*CompanyDetails, error) {
+	ctx = withOperation(ctx, "Company")
+
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.CompanyDetails, securityID)
 
 	var rawDetails CompanyDetailsRaw
@@ -309,6 +454,8 @@ func (c *Client) Company(ctx context.Context, securityID int32) (*CompanyDetails
 
 // CompanyBySymbol returns comprehensive information for a security by ticker symbol.
 func (c *Client) CompanyBySymbol(ctx context.Context, symbol string) (*CompanyDetails, error) {
+	ctx = withOperation(ctx, "CompanyBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
@@ -319,6 +466,8 @@ func (c *Client) CompanyBySymbol(ctx context.Context, symbol string) (*CompanyDe
 // SecurityDetail returns comprehensive security information including shareholding data.
 // This uses a POST request to fetch additional data not available via [Client.Company].
 func (c *Client) SecurityDetail(ctx context.Context, securityID int32) (*SecurityDetail, error) {
+	ctx = withOperation(ctx, "SecurityDetail")
+
 	payloadID, err := c.computeScripGraphPayloadID(ctx)
 	if err != nil {
 		return nil, err
@@ -328,8 +477,9 @@ func (c *Client) SecurityDetail(ctx context.Context, securityID int32) (*Securit
 
 	var raw SecurityDetailRaw
 	if err := c.apiPostRequest(ctx, endpoint, graphPostPayload{ID: payloadID}, &raw); err != nil {
-		return nil, err
+		return nil, c.payloadRejected(err)
 	}
+	c.registry.observeISIN(raw.Security.ID, raw.Security.Isin)
 
 	return &SecurityDetail{
 		ID:               raw.Security.ID,
@@ -364,6 +514,8 @@ func (c *Client) SecurityDetail(ctx context.Context, securityID int32) (*Securit
 
 // SecurityDetailBySymbol returns comprehensive security information by ticker symbol.
 func (c *Client) SecurityDetailBySymbol(ctx context.Context, symbol string) (*SecurityDetail, error) {
+	ctx = withOperation(ctx, "SecurityDetailBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
@@ -374,17 +526,25 @@ func (c *Client) SecurityDetailBySymbol(ctx context.Context, symbol string) (*Se
 // DebugSecurityDetailRaw returns the raw JSON response from the security detail endpoint.
 // This is useful for debugging the API response structure.
 func (c *Client) DebugSecurityDetailRaw(ctx context.Context, securityID int32) ([]byte, error) {
+	ctx = withOperation(ctx, "DebugSecurityDetailRaw")
+
 	payloadID, err := c.computeScripGraphPayloadID(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	endpoint := fmt.Sprintf("%s/%d", c.config.Endpoints.CompanyDetails, securityID)
-	return c.apiPostRequestRaw(ctx, endpoint, graphPostPayload{ID: payloadID})
+	data, err := c.apiPostRequestRaw(ctx, endpoint, graphPostPayload{ID: payloadID})
+	if err != nil {
+		return nil, c.payloadRejected(err)
+	}
+	return data, nil
 }
 
 // SectorScrips returns a map of sector names to their constituent security symbols.
 func (c *Client) SectorScrips(ctx context.Context) (SectorScrips, error) {
+	ctx = withOperation(ctx, "SectorScrips")
+
 	// Use company list which includes sector information
 	companies, err := c.Companies(ctx)
 	if err != nil {
@@ -408,138 +568,136 @@ func (c *Client) SectorScrips(ctx context.Context) (SectorScrips, error) {
 }
 
 // FindSecurity returns the security with the given ID.
+// Lookups are served from the client's [SecurityRegistry].
 func (c *Client) FindSecurity(ctx context.Context, securityID int32) (*Security, error) {
+	ctx = withOperation(ctx, "FindSecurity")
 	return c.findSecurityByID(ctx, securityID)
 }
 
 // FindSecurityBySymbol returns the security with the given ticker symbol.
+// Lookups are served from the client's [SecurityRegistry].
 func (c *Client) FindSecurityBySymbol(ctx context.Context, symbol string) (*Security, error) {
+	ctx = withOperation(ctx, "FindSecurityBySymbol")
 	return c.findSecurityBySymbol(ctx, symbol)
 }
 
 func (c *Client) findSecurityByID(ctx context.Context, id int32) (*Security, error) {
-	if id <= 0 {
-		return nil, NewInvalidClientRequestError("security ID must be positive")
-	}
-
-	securities, err := c.Securities(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := range securities {
-		if securities[i].ID == id {
-			return &securities[i], nil
-		}
-	}
-
-	return nil, NewNotFoundError(fmt.Sprintf("security with ID %d", id))
+	return c.registry.Security(ctx, id)
 }
 
 func (c *Client) findSecurityBySymbol(ctx context.Context, symbol string) (*Security, error) {
-	symbol = strings.ToUpper(strings.TrimSpace(symbol))
-	if symbol == "" {
-		return nil, NewInvalidClientRequestError("symbol cannot be empty")
-	}
-
-	securities, err := c.Securities(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	for i := range securities {
-		if securities[i].Symbol == symbol {
-			return &securities[i], nil
-		}
-	}
-
-	return nil, NewNotFoundError("security with symbol " + symbol)
+	return c.registry.SecurityBySymbol(ctx, symbol)
 }
 
 // FloorSheet returns all trades executed on the exchange for the current trading day.
 // Handles both array and paginated response formats.
 // Note: Returns empty slice if no trades have occurred yet.
+// Use [Client.FloorSheetSeq] to stream a busy day without buffering it.
 func (c *Client) FloorSheet(ctx context.Context) ([]FloorSheetEntry, error) {
-	params := url.Values{}
-	params.Set("size", "500")
-	params.Set("sort", "contractId,desc")
-	endpoint := c.config.Endpoints.FloorSheet + "?" + params.Encode()
+	ctx = withOperation(ctx, "FloorSheet")
+	return collect(c.FloorSheetSeq(ctx, nil))
+}
 
-	data, err := c.apiRequestRaw(ctx, endpoint)
-	if err != nil {
-		return nil, err
-	}
+// FloorSheetSeq yields the current trading day's trades page by page,
+// newest first, fetching up to opts.Prefetch pages ahead. Breaking out of
+// the loop stops fetching. Iteration ends after the first error.
+func (c *Client) FloorSheetSeq(ctx context.Context, opts *PageOptions) iter.Seq2[FloorSheetEntry, error] {
+	ctx = withOperation(ctx, "FloorSheetSeq")
 
-	// Try direct array format (may be empty during market hours before trades occur).
-	var floorSheetArray []FloorSheetEntry
-	if err := json.Unmarshal(data, &floorSheetArray); err == nil {
-		return floorSheetArray, nil
-	}
+	return paginate(ctx, opts, func(ctx context.Context, page, size int) (*PaginatedResponse[FloorSheetEntry], error) {
+		params := url.Values{}
+		params.Set("size", strconv.Itoa(size))
+		params.Set("sort", "contractId,desc")
+		if page > 0 {
+			params.Set("page", strconv.Itoa(page))
+		}
+		data, err := c.apiRequestRaw(ctx, c.config.Endpoints.FloorSheet+"?"+params.Encode())
+		if err != nil {
+			return nil, err
+		}
 
-	// Try paginated format.
-	var firstPage FloorSheetResponse
-	if err := json.Unmarshal(data, &firstPage); err != nil {
-		return nil, NewInvalidServerResponseError("unrecognized floor sheet response format")
-	}
+		// Try direct array format (may be empty during market hours before trades occur).
+		var floorSheetArray []FloorSheetEntry
+		if err := json.Unmarshal(data, &floorSheetArray); err == nil {
+			return &PaginatedResponse[FloorSheetEntry]{Content: floorSheetArray, TotalPages: 1}, nil
+		}
 
-	all := firstPage.FloorSheets.Content
-	total := firstPage.FloorSheets.TotalPages
-	for p := int32(1); p < total; p++ {
-		pageEndpoint := fmt.Sprintf("%s&page=%d", endpoint, p)
-		var page FloorSheetResponse
-		if err := c.apiRequest(ctx, pageEndpoint, &page); err != nil {
-			return nil, err
+		// Try paginated format.
+		var resp FloorSheetResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, NewInvalidServerResponseError("unrecognized floor sheet response format")
 		}
-		all = append(all, page.FloorSheets.Content...)
-	}
-	return all, nil
+		return &resp.FloorSheets, nil
+	})
 }
 
 // FloorSheetOf returns all trades for a specific security on a given business date.
 //
 // IMPORTANT: As of December 2025, NEPSE has blocked this endpoint at the server level.
-// All requests return 403 Forbidden. Use [Client.FloorSheet] instead for general floorsheet data.
-func (c *Client) FloorSheetOf(ctx context.Context, securityID int32, businessDate string) ([]FloorSheetEntry, error) {
-	params := url.Values{}
-	params.Set("businessDate", businessDate)
-	params.Set("size", "500")
-	params.Set("sort", "contractid,desc")
-	endpoint := fmt.Sprintf("%s/%d?%s", c.config.Endpoints.CompanyFloorsheet, securityID, params.Encode())
-
-	var firstPage FloorSheetResponse
-	if err := c.apiRequest(ctx, endpoint, &firstPage); err != nil {
-		return nil, err
-	}
+// All requests return 403 Forbidden, reported as [ErrEndpointBlocked].
+// Use [Client.FloorSheet] instead for general floorsheet data.
+func (c *Client) FloorSheetOf(ctx context.Context, securityID int32, businessDate Date) ([]FloorSheetEntry, error) {
+	ctx = withOperation(ctx, "FloorSheetOf")
+	return collect(c.FloorSheetOfSeq(ctx, securityID, businessDate, nil))
+}
 
-	if len(firstPage.FloorSheets.Content) == 0 {
-		return []FloorSheetEntry{}, nil
-	}
+// FloorSheetOfSeq yields a security's trades on a business date page by
+// page, like [Client.FloorSheetSeq]. It is subject to the same server-side
+// block as [Client.FloorSheetOf].
+func (c *Client) FloorSheetOfSeq(ctx context.Context, securityID int32, businessDate Date, opts *PageOptions) iter.Seq2[FloorSheetEntry, error] {
+	ctx = withOperation(ctx, "FloorSheetOfSeq")
 
-	allEntries := firstPage.FloorSheets.Content
-	totalPages := firstPage.FloorSheets.TotalPages
+	return paginate(ctx, opts, func(ctx context.Context, page, size int) (*PaginatedResponse[FloorSheetEntry], error) {
+		params := url.Values{}
+		params.Set("businessDate", businessDate.String())
+		params.Set("size", strconv.Itoa(size))
+		params.Set("sort", "contractid,desc")
+		if page > 0 {
+			params.Set("page", strconv.Itoa(page))
+		}
+		endpoint := fmt.Sprintf("%s/%d?%s", c.config.Endpoints.CompanyFloorsheet, securityID, params.Encode())
 
-	for page := int32(1); page < totalPages; page++ {
-		pageEndpoint := fmt.Sprintf("%s&page=%d", endpoint, page)
+		var resp FloorSheetResponse
+		if err := c.apiRequest(ctx, endpoint, &resp); err != nil {
+			return nil, blockedIfForbidden("FloorSheetOf", err)
+		}
+		return &resp.FloorSheets, nil
+	})
+}
 
-		var pageResponse FloorSheetResponse
-		if err := c.apiRequest(ctx, pageEndpoint, &pageResponse); err != nil {
+// collect drains seq into a slice, which is empty rather than nil when seq
+// yields nothing.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	all := []T{}
+	for v, err := range seq {
+		if err != nil {
 			return nil, err
 		}
-
-		allEntries = append(allEntries, pageResponse.FloorSheets.Content...)
+		all = append(all, v)
 	}
-
-	return allEntries, nil
+	return all, nil
 }
 
 // FloorSheetBySymbol returns all trades for a specific security by symbol on a given date.
 //
 // HACK: As of December 2025, NEPSE has blocked this endpoint at the server level.
-// All requests return 403 Forbidden. Use [Client.FloorSheet] instead for general floorsheet data.
-func (c *Client) FloorSheetBySymbol(ctx context.Context, symbol string, businessDate string) ([]FloorSheetEntry, error) {
+// All requests return 403 Forbidden, reported as [ErrEndpointBlocked]. Use [Client.FloorSheet] instead for general floorsheet data.
+func (c *Client) FloorSheetBySymbol(ctx context.Context, symbol string, businessDate Date) ([]FloorSheetEntry, error) {
+	ctx = withOperation(ctx, "FloorSheetBySymbol")
+
 	security, err := c.findSecurityBySymbol(ctx, symbol)
 	if err != nil {
 		return nil, err
 	}
 	return c.FloorSheetOf(ctx, security.ID, businessDate)
 }
+
+// blockedIfForbidden reports a 403 from an endpoint NEPSE is known to block
+// as [ErrEndpointBlocked], keeping the original error wrapped.
+func blockedIfForbidden(endpoint string, err error) error {
+	var nerr *NepseError
+	if errors.As(err, &nerr) && nerr.Type == ErrorTypeUnauthorized && nerr.StatusCode == http.StatusForbidden {
+		return NewEndpointBlockedError(endpoint, nerr)
+	}
+	return err
+}
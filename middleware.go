@@ -0,0 +1,65 @@
+package nepse
+
+import (
+	"context"
+	"net/http"
+)
+
+// Doer sends an HTTP request and returns its response. [*http.Client] satisfies it.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to the [Doer] interface.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a [Doer] to observe or modify requests and responses.
+// Middleware sees every HTTP attempt the client makes, including token
+// acquisition and retries, after all NEPSE headers have been set.
+// Use [Operation] on the request context to find the client method that
+// triggered the call.
+//
+// Example:
+//
+//	func timing(next nepse.Doer) nepse.Doer {
+//		return nepse.DoerFunc(func(req *http.Request) (*http.Response, error) {
+//			start := time.Now()
+//			resp, err := next.Do(req)
+//			log.Printf("%s took %v", nepse.Operation(req.Context()), time.Since(start))
+//			return resp, err
+//		})
+//	}
+type Middleware func(next Doer) Doer
+
+// chainMiddleware wraps base so that the first middleware is the outermost.
+func chainMiddleware(base Doer, middleware []Middleware) Doer {
+	d := base
+	for i := len(middleware) - 1; i >= 0; i-- {
+		d = middleware[i](d)
+	}
+	return d
+}
+
+type operationKey struct{}
+
+// Operation returns the name of the client method that triggered the request
+// carried by ctx, such as "MarketDepth" or "CompanyBySymbol". Nested calls
+// keep the outermost name, so the security list fetch behind
+// CompanyBySymbol reports "CompanyBySymbol". Returns "" if unknown.
+func Operation(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+// withOperation tags ctx with op unless an outer call already tagged it.
+func withOperation(ctx context.Context, op string) context.Context {
+	if Operation(ctx) != "" {
+		return ctx
+	}
+	return context.WithValue(ctx, operationKey{}, op)
+}
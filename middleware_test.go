@@ -0,0 +1,159 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_MiddlewareSeesEveryCall(t *testing.T) {
+	var mu sync.Mutex
+	serverHeaders := map[string]string{}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		serverHeaders[r.URL.Path] = r.Header.Get("X-Team")
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			json.NewEncoder(w).Encode(tokenResponse())
+		case "/api/nots/security":
+			json.NewEncoder(w).Encode([]Security{{ID: 131, Symbol: "NABIL"}})
+		case "/api/nots/company/list":
+			json.NewEncoder(w).Encode([]Company{{ID: 131, Symbol: "NABIL"}})
+		case "/api/nots/nepse-data/marketdepth/131":
+			json.NewEncoder(w).Encode(MarketDepthRaw{TotalBuyQty: 10})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var order []string
+	ops := map[string]string{}
+	addHeader := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			order = append(order, "outer")
+			mu.Unlock()
+			req.Header.Set("X-Team", "quant")
+			return next.Do(req)
+		})
+	}
+	recordOp := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			order = append(order, "inner")
+			ops[req.URL.Path] = Operation(req.Context())
+			mu.Unlock()
+			return next.Do(req)
+		})
+	}
+
+	client, err := NewClient(&Options{
+		BaseURL:     server.URL,
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  0,
+		Middleware:  []Middleware{addHeader, recordOp},
+		Config: &Config{
+			BaseURL:   server.URL,
+			Endpoints: DefaultEndpoints(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	depth, err := client.MarketDepthBySymbol(context.Background(), "NABIL")
+	if err != nil {
+		t.Fatalf("MarketDepthBySymbol failed: %v", err)
+	}
+	if depth.TotalBuyQty != 10 {
+		t.Errorf("expected TotalBuyQty 10, got %d", depth.TotalBuyQty)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	wantPaths := []string{
+		"/api/authenticate/prove",
+		"/api/nots/security",
+		"/api/nots/company/list",
+		"/api/nots/nepse-data/marketdepth/131",
+	}
+	for _, path := range wantPaths {
+		if op := ops[path]; op != "MarketDepthBySymbol" {
+			t.Errorf("operation for %s = %q, want MarketDepthBySymbol", path, op)
+		}
+		if h := serverHeaders[path]; h != "quant" {
+			t.Errorf("X-Team header for %s = %q, want quant", path, h)
+		}
+	}
+	for i := 0; i+1 < len(order); i += 2 {
+		if order[i] != "outer" || order[i+1] != "inner" {
+			t.Fatalf("middleware ran out of order: %v", order)
+		}
+	}
+}
+
+func TestClient_MiddlewareRewritesEndpoint(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/proxy/api/authenticate/prove" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tokenResponse())
+			return
+		}
+		http.NotFound(w, r)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	proxy, _ := url.Parse(server.URL)
+	rewrite := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req.URL.Scheme = proxy.Scheme
+			req.URL.Host = proxy.Host
+			req.URL.Path = "/proxy" + req.URL.Path
+			req.Host = proxy.Host
+			return next.Do(req)
+		})
+	}
+
+	client, err := NewClient(&Options{
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  0,
+		Middleware:  []Middleware{rewrite},
+		Config: &Config{
+			BaseURL: "https://nepse.invalid",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Token(context.Background()); err != nil {
+		t.Fatalf("Token() through rewriting middleware failed: %v", err)
+	}
+}
+
+func TestOperation(t *testing.T) {
+	ctx := context.Background()
+	if op := Operation(ctx); op != "" {
+		t.Errorf("expected empty operation, got %q", op)
+	}
+	ctx = withOperation(ctx, "CompanyBySymbol")
+	ctx = withOperation(ctx, "Company")
+	if op := Operation(ctx); op != "CompanyBySymbol" {
+		t.Errorf("expected outermost operation to win, got %q", op)
+	}
+}
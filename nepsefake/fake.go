@@ -0,0 +1,96 @@
+// Package nepsefake provides Fake, a programmable in-memory implementation
+// of [nepse.API] for unit tests of code that depends on the client.
+//
+// Unlike nepsetest, which serves NEPSE's HTTP API to a real client, a Fake
+// replaces the client entirely: set the Func field for each method the code
+// under test calls, and inspect the recorded calls afterwards.
+//
+// Example:
+//
+//	fake := &nepsefake.Fake{
+//		MarketStatusFunc: func(ctx context.Context) (*nepse.MarketStatus, error) {
+//			return &nepse.MarketStatus{IsOpen: "OPEN"}, nil
+//		},
+//	}
+//	svc := NewService(fake) // takes a nepse.API
+//	...
+//	if n := len(fake.Calls("MarketStatus")); n != 1 {
+//		t.Errorf("MarketStatus called %d times", n)
+//	}
+//
+// Set the Func fields before sharing a Fake between goroutines; recording
+// calls is safe for concurrent use.
+package nepsefake
+
+//go:generate go run ./internal/gen -o fake_gen.go ../api.go
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+var _ nepse.API = (*Fake)(nil)
+
+// ErrNotStubbed is returned by methods whose Func field is nil.
+var ErrNotStubbed = errors.New("nepsefake: method not stubbed")
+
+func notStubbed(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotStubbed, method)
+}
+
+// notStubbedSeq is the sequence returned by unstubbed iterator methods. It
+// yields ErrNotStubbed once.
+func notStubbedSeq[T any](method string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, notStubbed(method))
+	}
+}
+
+// Call is one recorded method call. Args holds the arguments after the
+// context, in order.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// recorder keeps the calls made to a Fake.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...any) {
+	r.mu.Lock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+	r.mu.Unlock()
+}
+
+// Calls returns the calls made to method, oldest first, or every call when
+// method is empty.
+func (r *recorder) Calls(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if method == "" {
+		return slices.Clone(r.calls)
+	}
+	var out []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Reset forgets all recorded calls. Func fields are kept.
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	r.calls = nil
+	r.mu.Unlock()
+}
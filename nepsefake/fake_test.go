@@ -0,0 +1,90 @@
+package nepsefake
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+// topGainer is code under test that depends only on the interface.
+func topGainer(ctx context.Context, api nepse.MarketData) (string, error) {
+	gainers, err := api.TopGainers(ctx)
+	if err != nil || len(gainers) == 0 {
+		return "", err
+	}
+	return gainers[0].Symbol, nil
+}
+
+func TestFake_Stub(t *testing.T) {
+	fake := &Fake{
+		TopGainersFunc: func(ctx context.Context) ([]nepse.TopGainerLoserEntry, error) {
+			return []nepse.TopGainerLoserEntry{{Symbol: "NABIL"}}, nil
+		},
+	}
+
+	got, err := topGainer(context.Background(), fake)
+	if err != nil {
+		t.Fatalf("topGainer failed: %v", err)
+	}
+	if got != "NABIL" {
+		t.Errorf("topGainer = %q, want NABIL", got)
+	}
+	if n := len(fake.Calls("TopGainers")); n != 1 {
+		t.Errorf("TopGainers called %d times, want 1", n)
+	}
+}
+
+func TestFake_NotStubbed(t *testing.T) {
+	fake := &Fake{}
+
+	history, err := fake.PriceHistory(context.Background(), 131, nepse.DateRange{From: nepse.NewDate(2026, 1, 1)})
+	if !errors.Is(err, ErrNotStubbed) {
+		t.Fatalf("err = %v, want ErrNotStubbed", err)
+	}
+	if history != nil {
+		t.Errorf("history = %v, want nil", history)
+	}
+
+	calls := fake.Calls("")
+	if len(calls) != 1 {
+		t.Fatalf("recorded %d calls, want 1", len(calls))
+	}
+	want := []any{int32(131), nepse.DateRange{From: nepse.NewDate(2026, 1, 1)}}
+	for i, arg := range calls[0].Args {
+		if arg != want[i] {
+			t.Errorf("arg %d = %v, want %v", i, arg, want[i])
+		}
+	}
+
+	fake.Reset()
+	if n := len(fake.Calls("")); n != 0 {
+		t.Errorf("%d calls after Reset, want 0", n)
+	}
+}
+
+func TestFake_Concurrent(t *testing.T) {
+	fake := &Fake{
+		MarketStatusFunc: func(ctx context.Context) (*nepse.MarketStatus, error) {
+			return &nepse.MarketStatus{IsOpen: "OPEN"}, nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			_, _ = fake.MarketStatus(context.Background())
+			_, _ = fake.MarketSummary(context.Background())
+		})
+	}
+	wg.Wait()
+
+	if n := len(fake.Calls("MarketStatus")); n != 50 {
+		t.Errorf("MarketStatus called %d times, want 50", n)
+	}
+	if n := len(fake.Calls("")); n != 100 {
+		t.Errorf("%d calls recorded, want 100", n)
+	}
+}
@@ -0,0 +1,247 @@
+// Command gen writes nepsefake's Fake from the interfaces in the nepse
+// package's api.go. Run it with go generate ./nepsefake.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"slices"
+	"strings"
+)
+
+func main() {
+	out := flag.String("o", "fake_gen.go", "output file")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("usage: gen [-o fake_gen.go] path/to/api.go")
+	}
+
+	src, err := generate(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// method is one interface method with its parameter and result types
+// already qualified with the nepse package name.
+type method struct {
+	name    string
+	params  []param
+	results []string
+	seqOf   string // element type when the only result is iter.Seq2[T, error]
+}
+
+type param struct {
+	name, typ string
+}
+
+// generate parses the API interface in path, expanding embedded interfaces
+// declared in the same file, and returns the formatted fake.
+func generate(path string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := map[string]string{} // package name to import path
+	for _, spec := range file.Imports {
+		path := strings.Trim(spec.Path.Value, `"`)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+	used := map[string]bool{}
+
+	ifaces := map[string]*ast.InterfaceType{}
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				ifaces[ts.Name.Name] = it
+			}
+		}
+	}
+	if ifaces["API"] == nil {
+		return nil, fmt.Errorf("%s: no API interface", path)
+	}
+
+	var methods []method
+	var collect func(name string) error
+	collect = func(name string) error {
+		it, ok := ifaces[name]
+		if !ok {
+			return fmt.Errorf("%s: embedded interface %s not declared in this file", path, name)
+		}
+		for _, field := range it.Methods.List {
+			ft, ok := field.Type.(*ast.FuncType)
+			if !ok {
+				if err := collect(field.Type.(*ast.Ident).Name); err != nil {
+					return err
+				}
+				continue
+			}
+			m, err := newMethod(fset, field.Names[0].Name, ft, used)
+			if err != nil {
+				return err
+			}
+			methods = append(methods, m)
+		}
+		return nil
+	}
+	if err := collect("API"); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for name := range used {
+		imp, ok := imports[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: package %s is not imported", path, name)
+		}
+		paths = append(paths, imp)
+	}
+	slices.Sort(paths)
+
+	var b bytes.Buffer
+	write(&b, paths, methods)
+	return format.Source(b.Bytes())
+}
+
+func newMethod(fset *token.FileSet, name string, ft *ast.FuncType, used map[string]bool) (method, error) {
+	m := method{name: name}
+	for _, field := range ft.Params.List {
+		typ, err := typeString(fset, field.Type, used)
+		if err != nil {
+			return m, err
+		}
+		if len(field.Names) == 0 {
+			return m, fmt.Errorf("%s: parameters must be named", name)
+		}
+		for _, n := range field.Names {
+			m.params = append(m.params, param{name: n.Name, typ: typ})
+		}
+	}
+	for _, field := range ft.Results.List {
+		typ, err := typeString(fset, field.Type, used)
+		if err != nil {
+			return m, err
+		}
+		m.results = append(m.results, typ)
+	}
+	if len(m.results) == 1 && strings.HasPrefix(m.results[0], "iter.Seq2[") && strings.HasSuffix(m.results[0], ", error]") {
+		m.seqOf = strings.TrimSuffix(strings.TrimPrefix(m.results[0], "iter.Seq2["), ", error]")
+		return m, nil
+	}
+	if len(m.results) == 0 || m.results[len(m.results)-1] != "error" {
+		return m, fmt.Errorf("%s: last result must be error or the result an iter.Seq2[T, error]", name)
+	}
+	return m, nil
+}
+
+// typeString prints expr with identifiers declared in package nepse
+// qualified, so the fake can name them from outside the package, and marks
+// the packages it refers to as used.
+func typeString(fset *token.FileSet, expr ast.Expr, used map[string]bool) (string, error) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			used[n.X.(*ast.Ident).Name] = true
+			return false
+		case *ast.Ident:
+			if ast.IsExported(n.Name) {
+				n.Name = "nepse." + n.Name
+			}
+		}
+		return true
+	})
+	var b strings.Builder
+	if err := printer.Fprint(&b, fset, expr); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func write(b *bytes.Buffer, imports []string, methods []method) {
+	b.WriteString(`// Code generated by nepsefake/internal/gen from api.go; DO NOT EDIT.
+
+package nepsefake
+
+import (
+`)
+	for _, path := range imports {
+		fmt.Fprintf(b, "\t%q\n", path)
+	}
+	b.WriteString(`
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+// Fake is a programmable implementation of [nepse.API]. Each method calls
+// the matching Func field, or returns [ErrNotStubbed] when it is nil, and
+// records the call either way.
+type Fake struct {
+	recorder
+
+`)
+	for _, m := range methods {
+		fmt.Fprintf(b, "\t%sFunc func(%s) (%s)\n", m.name, m.paramList(), strings.Join(m.results, ", "))
+	}
+	b.WriteString("}\n")
+
+	for _, m := range methods {
+		fmt.Fprintf(b, "\n// %s records the call and runs %sFunc.\n", m.name, m.name)
+		fmt.Fprintf(b, "func (f *Fake) %s(%s) (%s) {\n", m.name, m.paramList(), strings.Join(m.results, ", "))
+		fmt.Fprintf(b, "\tf.record(%q%s)\n", m.name, m.argList(true))
+		fmt.Fprintf(b, "\tif f.%sFunc == nil {\n", m.name)
+		if m.seqOf != "" {
+			fmt.Fprintf(b, "\t\treturn notStubbedSeq[%s](%q)\n\t}\n", m.seqOf, m.name)
+		} else {
+			var zeros []string
+			for i, r := range m.results[:len(m.results)-1] {
+				fmt.Fprintf(b, "\t\tvar r%d %s\n", i, r)
+				zeros = append(zeros, fmt.Sprintf("r%d", i))
+			}
+			zeros = append(zeros, fmt.Sprintf("notStubbed(%q)", m.name))
+			fmt.Fprintf(b, "\t\treturn %s\n\t}\n", strings.Join(zeros, ", "))
+		}
+		fmt.Fprintf(b, "\treturn f.%sFunc(%s)\n}\n", m.name, strings.TrimPrefix(m.argList(false), ", "))
+	}
+}
+
+func (m method) paramList() string {
+	var parts []string
+	for _, p := range m.params {
+		parts = append(parts, p.name+" "+p.typ)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// argList returns the parameter names, each prefixed with ", ". The
+// recorded form leaves out the context.
+func (m method) argList(recorded bool) string {
+	var b strings.Builder
+	for _, p := range m.params {
+		if recorded && p.typ == "context.Context" {
+			continue
+		}
+		b.WriteString(", " + p.name)
+	}
+	return b.String()
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestFakeUpToDate(t *testing.T) {
+	got, err := generate("../../../api.go")
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	want, err := os.ReadFile("../../fake_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("nepsefake/fake_gen.go is stale; run go generate ./nepsefake")
+	}
+}
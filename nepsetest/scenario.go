@@ -0,0 +1,121 @@
+package nepsetest
+
+import (
+	"slices"
+	"time"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+// Scenario is the market data a [Server] serves. Fields left empty produce
+// empty but well-formed responses, so a zero Scenario is a valid quiet day.
+type Scenario struct {
+	// MarketStatus is served by the market-open endpoint. Its ID also feeds
+	// the POST payload ID the server expects for graph and detail requests.
+	MarketStatus nepse.MarketStatus
+
+	MarketSummary []nepse.MarketSummaryItem
+	LiveMarket    []nepse.LiveMarketEntry
+	Securities    []nepse.Security
+	Companies     []nepse.Company
+	TodaysPrices  []nepse.TodayPrice
+
+	// FloorSheet is paginated by the floor sheet endpoints using the
+	// request's size and page parameters. The per-security endpoint serves
+	// the entries whose SecurityID matches.
+	FloorSheet []nepse.FloorSheetEntry
+
+	// PriceHistory is keyed by security ID, filtered by the request's
+	// startDate and endDate, and paginated like FloorSheet.
+	PriceHistory map[int32][]nepse.PriceHistory
+
+	// Graphs is keyed by request path, such as "/api/nots/graph/index/58" or
+	// "/api/nots/market/graphdata/daily/131".
+	Graphs map[string][]nepse.GraphDataPoint
+
+	// Responses overrides the body for any request path (without query).
+	// Values are encoded as JSON. Use it for endpoints the typed fields
+	// above do not cover, such as market depth or company profiles.
+	Responses map[string]any
+}
+
+// DefaultScenario returns a small open-market scenario with two securities,
+// suitable for tests that only need the API to answer sensibly.
+func DefaultScenario() Scenario {
+	return Scenario{
+		MarketStatus: nepse.MarketStatus{IsOpen: "OPEN", AsOf: nepse.DateTimeOf(time.Date(2026, 1, 2, 13, 0, 0, 0, nepse.NPT)), ID: 61},
+		MarketSummary: []nepse.MarketSummaryItem{
+			{Detail: "Total Turnover Rs:", Value: 4_512_345_678.5},
+			{Detail: "Total Traded Shares", Value: 11_234_567},
+			{Detail: "Total Transactions", Value: 56_789},
+			{Detail: "Total Scrips Traded", Value: 312},
+		},
+		Securities: []nepse.Security{
+			{ID: 131, Symbol: "NABIL", SecurityName: "Nabil Bank Limited", ActiveStatus: "A"},
+			{ID: 2790, Symbol: "NHPC", SecurityName: "National Hydro Power Company Limited", ActiveStatus: "A"},
+		},
+		Companies: []nepse.Company{
+			{ID: 131, Symbol: "NABIL", CompanyName: "Nabil Bank Limited", SectorName: "Commercial Banks"},
+			{ID: 2790, Symbol: "NHPC", CompanyName: "National Hydro Power Company Limited", SectorName: "Hydro Power"},
+		},
+		TodaysPrices: []nepse.TodayPrice{
+			{SecurityID: 131, Symbol: "NABIL", ClosePrice: 512.5, LastTradedPrice: 512.5, BusinessDate: nepse.NewDate(2026, 1, 2)},
+			{SecurityID: 2790, Symbol: "NHPC", ClosePrice: 8.4, LastTradedPrice: 8.4, BusinessDate: nepse.NewDate(2026, 1, 2)},
+		},
+	}
+}
+
+// floorSheetFor returns the floor sheet entries for securityID.
+func (sc *Scenario) floorSheetFor(securityID int32) []nepse.FloorSheetEntry {
+	var out []nepse.FloorSheetEntry
+	for _, e := range sc.FloorSheet {
+		if e.SecurityID == securityID {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// priceHistoryFor returns price history for securityID between start and end
+// (inclusive, YYYY-MM-DD; empty means unbounded), newest first like NEPSE.
+func (sc *Scenario) priceHistoryFor(securityID int32, start, end string) []nepse.PriceHistory {
+	var out []nepse.PriceHistory
+	for _, p := range sc.PriceHistory[securityID] {
+		day := p.BusinessDate.String()
+		if (start != "" && day < start) || (end != "" && day > end) {
+			continue
+		}
+		out = append(out, p)
+	}
+	slices.SortFunc(out, func(a, b nepse.PriceHistory) int {
+		return b.BusinessDate.Compare(a.BusinessDate)
+	})
+	return out
+}
+
+// paginate returns page (0-based) of items with the given size in NEPSE's
+// Spring-style page envelope.
+func paginate[T any](items []T, page, size int) nepse.PaginatedResponse[T] {
+	if size <= 0 {
+		size = 500
+	}
+	page = max(page, 0)
+	total := len(items)
+	pages := (total + size - 1) / size
+	lo := min(page*size, total)
+	hi := min(lo+size, total)
+	content := items[lo:hi]
+	if content == nil {
+		content = []T{}
+	}
+	return nepse.PaginatedResponse[T]{
+		Content:          content,
+		PageNumber:       int32(page),
+		Size:             int32(size),
+		TotalElements:    int64(total),
+		TotalPages:       int32(pages),
+		First:            page == 0,
+		Last:             page >= pages-1,
+		NumberOfElements: int32(len(content)),
+	}
+}
@@ -0,0 +1,471 @@
+// Package nepsetest provides an in-process fake NEPSE API server for tests.
+//
+// The server issues obfuscated tokens that the client's embedded WASM parser
+// decodes, enforces the "Salter" authorization header, serves every path in
+// [nepse.DefaultEndpoints], and rejects graph and security-detail POSTs whose
+// payload ID does not match NEPSE's algorithm. Market data comes from a
+// [Scenario], and faults such as 401s, 429s, and 5xx can be injected.
+//
+// Example:
+//
+//	srv := nepsetest.NewServer(nepsetest.DefaultScenario())
+//	defer srv.Close()
+//
+//	client, err := nepse.NewClient(srv.Options())
+package nepsetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	nepse "github.com/voidarchive/go-nepse"
+	"github.com/voidarchive/go-nepse/internal/auth"
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+// tokenPath and refreshPath are NEPSE's token endpoints.
+const (
+	tokenPath   = "/api/authenticate/prove"
+	refreshPath = "/api/authenticate/refresh-token"
+)
+
+// tokenLength is the length of issued access tokens. Real NEPSE tokens are
+// JWTs well over 120 characters, which the WASM-computed indices rely on.
+const tokenLength = 160
+
+// Fault makes the server fail matching requests instead of serving them.
+type Fault struct {
+	Path       string // Path prefix to match; "" matches every request, including token requests
+	Method     string // Request method to match; "" matches any
+	Status     int    // Status code to return
+	RetryAfter string // Optional Retry-After header value
+	Body       string // Optional response body
+	Times      int    // Number of requests to fail; 0 fails every matching request
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, f.Path) && (f.Method == "" || f.Method == r.Method)
+}
+
+// Server is a fake NEPSE API. Create one with [NewServer] and release it with
+// [Server.Close]. All methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	obf       *auth.Obfuscator
+	endpoints nepse.Endpoints
+
+	mu       sync.Mutex
+	scenario Scenario
+	tokens   map[string]auth.Salts // issued access token -> salts it was issued with
+	refresh  map[string]bool       // issued refresh tokens not yet used
+	faults   []*Fault
+	hits     map[string]int
+	now      func() time.Time
+}
+
+// NewServer starts a fake NEPSE server serving sc. It panics if the token
+// obfuscator cannot be initialised, like [httptest.NewServer] does when it
+// cannot listen.
+func NewServer(sc Scenario) *Server {
+	obf, err := auth.NewObfuscator()
+	if err != nil {
+		panic(fmt.Sprintf("nepsetest: %v", err))
+	}
+	s := &Server{
+		obf:       obf,
+		endpoints: nepse.DefaultEndpoints(),
+		scenario:  sc,
+		tokens:    make(map[string]auth.Salts),
+		refresh:   make(map[string]bool),
+		hits:      make(map[string]int),
+		now:       time.Now,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close shuts down the server and releases the token obfuscator.
+func (s *Server) Close() {
+	s.Server.Close()
+	_ = s.obf.Close()
+}
+
+// Options returns client options pointed at the server. Retries keep the
+// default budget but start from a 1ms delay so fault tests run quickly.
+func (s *Server) Options() *nepse.Options {
+	opts := nepse.DefaultOptions()
+	opts.BaseURL = s.URL
+	opts.HTTPTimeout = 5 * time.Second
+	opts.RetryDelay = time.Millisecond
+	opts.Config = &nepse.Config{
+		BaseURL:   s.URL,
+		Endpoints: nepse.DefaultEndpoints(),
+	}
+	return opts
+}
+
+// Update calls fn with the live scenario so tests can change market data
+// between calls.
+func (s *Server) Update(fn func(sc *Scenario)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.scenario)
+}
+
+// Inject adds a fault. Faults are checked in the order they were added.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// ExpireTokens revokes every issued access token, so the next authenticated
+// request receives a 401 as it would after NEPSE rotates tokens. Refresh
+// tokens stay valid.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.tokens)
+}
+
+// RevokeRefreshTokens makes every issued refresh token unusable, so clients
+// must prove again.
+func (s *Server) RevokeRefreshTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.refresh)
+}
+
+// Hits returns how many requests the server received for path, including
+// faulted and rejected ones.
+func (s *Server) Hits(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[path]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.hits[r.URL.Path]++
+	fault := s.takeFault(r)
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.RetryAfter != "" {
+			w.Header().Set("Retry-After", fault.RetryAfter)
+		}
+		w.WriteHeader(fault.Status)
+		_, _ = io.WriteString(w, fault.Body)
+		return
+	}
+
+	switch r.URL.Path {
+	case tokenPath:
+		s.serveToken(w)
+		return
+	case refreshPath:
+		s.serveRefresh(w, r)
+		return
+	}
+
+	salts, ok := s.authorize(r)
+	if !ok {
+		http.Error(w, `{"message":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	s.serveAPI(w, r, salts)
+}
+
+// takeFault returns the first fault matching r and consumes one use of it.
+// Callers must hold s.mu.
+func (s *Server) takeFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if !f.matches(r) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) serveToken(w http.ResponseWriter) {
+	access, refresh := randomToken(), randomToken()
+
+	var tr auth.TokenResponse
+	for {
+		salts := auth.Salts{
+			Salt1: 1000 + rand.N(90000),
+			Salt2: 1000 + rand.N(90000),
+			Salt3: 1000 + rand.N(90000),
+			Salt4: 1000 + rand.N(90000),
+			Salt5: 1000 + rand.N(90000),
+		}
+		var err error
+		tr, err = s.obf.Obfuscate(salts, access, refresh)
+		if errors.Is(err, auth.ErrUnusableSalts) {
+			continue
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		s.mu.Lock()
+		s.tokens[access] = salts
+		s.refresh[refresh] = true
+		s.mu.Unlock()
+		break
+	}
+
+	tr.ServerTime = s.now().UnixMilli()
+	writeJSON(w, tr)
+}
+
+// serveRefresh exchanges a refresh token for a new token pair. Refresh
+// tokens are single use.
+func (s *Server) serveRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var body struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	ok := s.refresh[body.RefreshToken]
+	delete(s.refresh, body.RefreshToken)
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, `{"message":"Invalid refresh token"}`, http.StatusUnauthorized)
+		return
+	}
+	s.serveToken(w)
+}
+
+// authorize checks the Salter header and returns the salts the presented
+// token was issued with.
+func (s *Server) authorize(r *http.Request) (auth.Salts, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Salter ")
+	if !ok {
+		return auth.Salts{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	salts, ok := s.tokens[token]
+	return salts, ok
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request, salts auth.Salts) {
+	s.mu.Lock()
+	sc := s.scenario
+	s.mu.Unlock()
+
+	e := s.endpoints
+	path := r.URL.Path
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	size, _ := strconv.Atoi(q.Get("size"))
+	if page < 0 {
+		// NEPSE rejects negative pages rather than serving the first one.
+		http.Error(w, `{"message":"page index must not be less than zero"}`, http.StatusBadRequest)
+		return
+	}
+
+	if isIndexGraph(e, path) {
+		if !s.checkPayload(w, r, sc, &salts) {
+			return
+		}
+		s.respond(w, sc, path, graphJSON(sc.Graphs[path]))
+		return
+	}
+
+	switch path {
+	case e.MarketSummary:
+		s.respond(w, sc, path, orEmpty(sc.MarketSummary))
+		return
+	case e.MarketOpen:
+		s.respond(w, sc, path, sc.MarketStatus)
+		return
+	case e.LiveMarket:
+		s.respond(w, sc, path, orEmpty(sc.LiveMarket))
+		return
+	case e.SupplyDemand:
+		s.respond(w, sc, path, map[string]any{"supplyList": []any{}, "demandList": []any{}})
+		return
+	case e.TodaysPrice:
+		s.respond(w, sc, path, orEmpty(sc.TodaysPrices))
+		return
+	case e.FloorSheet:
+		s.respond(w, sc, path, map[string]any{"floorsheets": paginate(sc.FloorSheet, page, size)})
+		return
+	case e.NepseIndex, e.TopGainers, e.TopLosers, e.TopTrade, e.TopTransaction, e.TopTurnover:
+		s.respond(w, sc, path, []any{})
+		return
+	case pathOf(e.SecurityList):
+		s.respond(w, sc, path, orEmpty(sc.Securities))
+		return
+	case e.CompanyList:
+		s.respond(w, sc, path, orEmpty(sc.Companies))
+		return
+	}
+
+	base, id, ok := splitID(path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch base {
+	case e.CompanyDetails:
+		if r.Method == http.MethodPost && !s.checkPayload(w, r, sc, nil) {
+			return
+		}
+		s.respond(w, sc, path, map[string]any{})
+	case e.CompanyPriceHistory:
+		history := sc.priceHistoryFor(id, q.Get("startDate"), q.Get("endDate"))
+		s.respond(w, sc, path, paginate(history, page, size))
+	case e.CompanyFloorsheet:
+		s.respond(w, sc, path, map[string]any{"floorsheets": paginate(sc.floorSheetFor(id), page, size)})
+	case e.MarketDepth, e.CompanyProfile:
+		s.respond(w, sc, path, map[string]any{})
+	case e.BoardOfDirectors, e.CorporateActions, e.Reports, e.Dividend:
+		s.respond(w, sc, path, []any{})
+	case e.CompanyDailyGraph:
+		if !s.checkPayload(w, r, sc, nil) {
+			return
+		}
+		s.respond(w, sc, path, graphJSON(sc.Graphs[path]))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// checkPayload verifies the POST payload ID. salts is nil for endpoints that
+// use only the base value. It writes a 400 and returns false on mismatch.
+func (s *Server) checkPayload(w http.ResponseWriter, r *http.Request, sc Scenario, salts *auth.Salts) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	var body struct {
+		ID *int `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ID == nil {
+		http.Error(w, `{"message":"missing payload id"}`, http.StatusBadRequest)
+		return false
+	}
+
+	// Accept the previous minute's day too, so a request computed just
+	// before midnight NPT is not rejected.
+	now := s.now().In(payload.NPT)
+	for _, t := range []time.Time{now, now.Add(-time.Minute)} {
+		day := t.Day()
+		want := payload.Base(int(sc.MarketStatus.ID), day)
+		if salts != nil {
+			want = payload.Index(want, day, *salts)
+		}
+		if *body.ID == want {
+			return true
+		}
+	}
+	http.Error(w, `{"message":"invalid payload id"}`, http.StatusBadRequest)
+	return false
+}
+
+// respond writes the scenario override for path if there is one, else v.
+func (s *Server) respond(w http.ResponseWriter, sc Scenario, path string, v any) {
+	if override, ok := sc.Responses[path]; ok {
+		v = override
+	}
+	writeJSON(w, v)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// orEmpty makes nil slices encode as [] rather than null.
+func orEmpty[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// graphJSON encodes graph points in NEPSE's [timestamp, value] form.
+func graphJSON(points []nepse.GraphDataPoint) [][2]float64 {
+	out := make([][2]float64, len(points))
+	for i, p := range points {
+		out[i] = [2]float64{float64(p.Timestamp.Time().Unix()), p.Value}
+	}
+	return out
+}
+
+// isIndexGraph reports whether path is one of the index graph endpoints.
+func isIndexGraph(e nepse.Endpoints, path string) bool {
+	switch path {
+	case e.GraphNepseIndex, e.GraphSensitiveIndex, e.GraphFloatIndex, e.GraphSensitiveFloatIndex,
+		e.GraphBankingSubindex, e.GraphDevBankSubindex, e.GraphFinanceSubindex, e.GraphHotelSubindex,
+		e.GraphHydroSubindex, e.GraphInvestmentSubindex, e.GraphLifeInsSubindex, e.GraphManufacturingSubindex,
+		e.GraphMicrofinanceSubindex, e.GraphMutualFundSubindex, e.GraphNonLifeInsSubindex,
+		e.GraphOthersSubindex, e.GraphTradingSubindex:
+		return true
+	}
+	return false
+}
+
+// splitID splits "/api/nots/security/131" into "/api/nots/security" and 131.
+func splitID(path string) (string, int32, bool) {
+	i := strings.LastIndexByte(path, '/')
+	if i <= 0 {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(path[i+1:], 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return path[:i], int32(id), true
+}
+
+// pathOf strips the query string from an endpoint.
+func pathOf(endpoint string) string {
+	path, _, _ := strings.Cut(endpoint, "?")
+	return path
+}
+
+// randomToken returns a random URL-safe token of tokenLength characters.
+func randomToken() string {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	b := make([]byte, tokenLength)
+	for i := range b {
+		b[i] = alphabet[rand.N(len(alphabet))]
+	}
+	return string(b)
+}
@@ -0,0 +1,454 @@
+package nepsetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	nepse "github.com/voidarchive/go-nepse"
+)
+
+func newClient(t *testing.T, srv *Server, mutate ...func(*nepse.Options)) *nepse.Client {
+	t.Helper()
+	opts := srv.Options()
+	for _, m := range mutate {
+		m(opts)
+	}
+	client, err := nepse.NewClient(opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestServer_DecodesTokenAndServesData(t *testing.T) {
+	srv := NewServer(DefaultScenario())
+	defer srv.Close()
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	summary, err := client.MarketSummary(ctx)
+	if err != nil {
+		t.Fatalf("MarketSummary failed: %v", err)
+	}
+	if summary.TotalScripsTraded != 312 {
+		t.Errorf("TotalScripsTraded = %v, want 312", summary.TotalScripsTraded)
+	}
+
+	security, err := client.FindSecurityBySymbol(ctx, "nhpc")
+	if err != nil {
+		t.Fatalf("FindSecurityBySymbol failed: %v", err)
+	}
+	if security.ID != 2790 {
+		t.Errorf("security ID = %d, want 2790", security.ID)
+	}
+
+	prices, err := client.TodaysPrices(ctx, nepse.Date{})
+	if err != nil {
+		t.Fatalf("TodaysPrices failed: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Errorf("expected 2 prices, got %d", len(prices))
+	}
+}
+
+func TestServer_ServesEveryDefaultEndpoint(t *testing.T) {
+	srv := NewServer(Scenario{})
+	defer srv.Close()
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"MarketSummary":     func() error { _, err := client.MarketSummary(ctx); return err },
+		"MarketStatus":      func() error { _, err := client.MarketStatus(ctx); return err },
+		"LiveMarket":        func() error { _, err := client.LiveMarket(ctx); return err },
+		"SupplyDemand":      func() error { _, err := client.SupplyDemand(ctx); return err },
+		"TodaysPrices":      func() error { _, err := client.TodaysPrices(ctx, nepse.Date{}); return err },
+		"FloorSheet":        func() error { _, err := client.FloorSheet(ctx); return err },
+		"NepseIndex":        func() error { _, err := client.SubIndices(ctx); return err },
+		"TopGainers":        func() error { _, err := client.TopGainers(ctx); return err },
+		"TopLosers":         func() error { _, err := client.TopLosers(ctx); return err },
+		"TopTenTrade":       func() error { _, err := client.TopTenTrade(ctx); return err },
+		"TopTenTransaction": func() error { _, err := client.TopTenTransaction(ctx); return err },
+		"TopTenTurnover":    func() error { _, err := client.TopTenTurnover(ctx); return err },
+		"Securities":        func() error { _, err := client.Securities(ctx); return err },
+		"Companies":         func() error { _, err := client.Companies(ctx); return err },
+		"Company":           func() error { _, err := client.Company(ctx, 131); return err },
+		"SecurityDetail":    func() error { _, err := client.SecurityDetail(ctx, 131); return err },
+		"PriceHistory": func() error {
+			_, err := client.PriceHistory(ctx, 131, nepse.DateRange{From: nepse.NewDate(2026, 1, 1)})
+			return err
+		},
+		"FloorSheetOf":     func() error { _, err := client.FloorSheetOf(ctx, 131, nepse.NewDate(2026, 1, 2)); return err },
+		"MarketDepth":      func() error { _, err := client.MarketDepth(ctx, 131); return err },
+		"CompanyProfile":   func() error { _, err := client.CompanyProfile(ctx, 131); return err },
+		"BoardOfDirectors": func() error { _, err := client.BoardOfDirectors(ctx, 131); return err },
+		"CorporateActions": func() error { _, err := client.CorporateActions(ctx, 131); return err },
+		"Reports":          func() error { _, err := client.Reports(ctx, 131); return err },
+		"Dividends":        func() error { _, err := client.Dividends(ctx, 131); return err },
+		"DailyScripGraph":  func() error { _, err := client.DailyScripGraph(ctx, 131); return err },
+	}
+	for i := nepse.IndexNepse; i <= nepse.IndexTrading; i++ {
+		calls[fmt.Sprintf("DailyIndexGraph(%d)", i)] = func() error { _, err := client.DailyIndexGraph(ctx, i); return err }
+	}
+
+	for name, call := range calls {
+		err := call()
+		// An empty scenario has no live data or prices; these errors mean the
+		// endpoint answered with an empty body, which is what is served.
+		if errors.Is(err, nepse.ErrEmptyResponse) || errors.Is(err, nepse.ErrMarketClosed) {
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s failed: %v", name, err)
+		}
+	}
+}
+
+func TestServer_GraphsUsePayloadIDs(t *testing.T) {
+	sc := DefaultScenario()
+	sc.Graphs = map[string][]nepse.GraphDataPoint{
+		"/api/nots/graph/index/58":             {{Timestamp: nepse.DateTimeOf(time.Unix(1767330000, 0)), Value: 2650.12}},
+		"/api/nots/market/graphdata/daily/131": {{Timestamp: nepse.DateTimeOf(time.Unix(1767330000, 0)), Value: 512.5}},
+	}
+	srv := NewServer(sc)
+	defer srv.Close()
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	index, err := client.DailyNepseIndexGraph(ctx)
+	if err != nil {
+		t.Fatalf("DailyNepseIndexGraph failed: %v", err)
+	}
+	if len(index.Data) != 1 || index.Data[0].Value != 2650.12 {
+		t.Errorf("unexpected index graph %+v", index.Data)
+	}
+
+	scrip, err := client.DailyScripGraph(ctx, 131)
+	if err != nil {
+		t.Fatalf("DailyScripGraph failed: %v", err)
+	}
+	if len(scrip.Data) != 1 || scrip.Data[0].Value != 512.5 {
+		t.Errorf("unexpected scrip graph %+v", scrip.Data)
+	}
+
+	_, err = client.DebugRawPostRequest(ctx, "/api/nots/graph/index/58", map[string]int{"id": -1})
+	if !errors.Is(err, nepse.ErrInvalidClientRequest) {
+		t.Errorf("expected wrong payload ID to be rejected, got %v", err)
+	}
+}
+
+func TestServer_AuthSelfTest(t *testing.T) {
+	srv := NewServer(DefaultScenario())
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	// The fake server strips nothing itself, so a passing self-test means the
+	// client decoded the obfuscated token and computed the payload IDs the
+	// server verifies.
+	report, err := client.AuthSelfTest(context.Background())
+	if err != nil {
+		t.Fatalf("AuthSelfTest failed:\n%s", report)
+	}
+	if len(report.AccessToken) != tokenLength || len(report.RawAccess) != tokenLength+5 {
+		t.Errorf("decoded %d chars from %d, want %d from %d",
+			len(report.AccessToken), len(report.RawAccess), tokenLength, tokenLength+5)
+	}
+}
+
+func TestServer_PaginatesFloorSheet(t *testing.T) {
+	sc := DefaultScenario()
+	for i := range 1234 {
+		sc.FloorSheet = append(sc.FloorSheet, nepse.FloorSheetEntry{
+			ContractID:  int64(i),
+			SecurityID:  131 + int32(i%2)*2659,
+			StockSymbol: "NABIL",
+		})
+	}
+	srv := NewServer(sc)
+	defer srv.Close()
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	all, err := client.FloorSheet(ctx)
+	if err != nil {
+		t.Fatalf("FloorSheet failed: %v", err)
+	}
+	if len(all) != 1234 {
+		t.Errorf("expected 1234 entries across pages, got %d", len(all))
+	}
+	if hits := srv.Hits("/api/nots/nepse-data/floorsheet"); hits != 3 {
+		t.Errorf("expected 3 page requests, got %d", hits)
+	}
+
+	of, err := client.FloorSheetOf(ctx, 131, nepse.NewDate(2026, 1, 2))
+	if err != nil {
+		t.Fatalf("FloorSheetOf failed: %v", err)
+	}
+	if len(of) != 617 {
+		t.Errorf("expected 617 entries for security 131, got %d", len(of))
+	}
+}
+
+func TestServer_RejectsNegativePage(t *testing.T) {
+	srv := NewServer(DefaultScenario())
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	_, err := nepse.Get[json.RawMessage](context.Background(), client, "/api/nots/nepse-data/floorsheet", url.Values{"page": {"-1"}})
+	if !errors.Is(err, nepse.ErrInvalidClientRequest) {
+		t.Errorf("expected ErrInvalidClientRequest for page -1, got %v", err)
+	}
+	if got := paginate([]int{1, 2, 3}, -1, 2); len(got.Content) != 2 || !got.First {
+		t.Errorf("paginate(-1) = %+v, want the first page", got)
+	}
+}
+
+func TestServer_FloorSheetSeq(t *testing.T) {
+	sc := DefaultScenario()
+	for i := range 1000 {
+		sc.FloorSheet = append(sc.FloorSheet, nepse.FloorSheetEntry{ContractID: int64(i), StockSymbol: "NABIL"})
+	}
+	srv := NewServer(sc)
+	defer srv.Close()
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	var got []int64
+	for e, err := range client.FloorSheetSeq(ctx, &nepse.PageOptions{PageSize: 30, Prefetch: 4}) {
+		if err != nil {
+			t.Fatalf("FloorSheetSeq failed: %v", err)
+		}
+		got = append(got, e.ContractID)
+	}
+	if len(got) != 1000 {
+		t.Fatalf("expected 1000 entries, got %d", len(got))
+	}
+	for i, id := range got {
+		if id != int64(i) {
+			t.Fatalf("entry %d has contract %d; pages arrived out of order", i, id)
+		}
+	}
+	if hits := srv.Hits("/api/nots/nepse-data/floorsheet"); hits != 34 {
+		t.Errorf("expected 34 page requests, got %d", hits)
+	}
+
+	// Stopping early stops fetching: the first page plus at most the
+	// current page and two prefetched ones.
+	before := srv.Hits("/api/nots/nepse-data/floorsheet")
+	n := 0
+	for _, err := range client.FloorSheetSeq(ctx, &nepse.PageOptions{PageSize: 10, Prefetch: 2}) {
+		if err != nil {
+			t.Fatalf("FloorSheetSeq failed: %v", err)
+		}
+		if n++; n == 15 {
+			break
+		}
+	}
+	if hits := srv.Hits("/api/nots/nepse-data/floorsheet") - before; hits > 4 {
+		t.Errorf("expected at most 4 page requests after stopping at row 15, got %d", hits)
+	}
+}
+
+func TestServer_PriceHistoryFiltersByDate(t *testing.T) {
+	sc := DefaultScenario()
+	sc.PriceHistory = map[int32][]nepse.PriceHistory{
+		131: {
+			{BusinessDate: nepse.NewDate(2025, 12, 31), ClosePrice: 500},
+			{BusinessDate: nepse.NewDate(2026, 1, 1), ClosePrice: 505},
+			{BusinessDate: nepse.NewDate(2026, 1, 2), ClosePrice: 512.5},
+		},
+	}
+	srv := NewServer(sc)
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	history, err := client.PriceHistory(context.Background(), 131, nepse.DateRange{From: nepse.NewDate(2026, 1, 1), To: nepse.NewDate(2026, 1, 31)})
+	if err != nil {
+		t.Fatalf("PriceHistory failed: %v", err)
+	}
+	if len(history) != 2 || history[0].BusinessDate != nepse.NewDate(2026, 1, 1) {
+		t.Errorf("unexpected history %+v", history)
+	}
+}
+
+func TestServer_PriceHistoryLongRange(t *testing.T) {
+	sc := DefaultScenario()
+	day := nepse.NewDate(2023, 1, 1)
+	var rows []nepse.PriceHistory
+	for i := range 1096 {
+		rows = append(rows, nepse.PriceHistory{BusinessDate: day.AddDays(i), ClosePrice: float64(i)})
+	}
+	sc.PriceHistory = map[int32][]nepse.PriceHistory{131: rows}
+	srv := NewServer(sc)
+	defer srv.Close()
+	client := newClient(t, srv)
+	ctx := context.Background()
+
+	// Three years is more than one 500-row page.
+	history, err := client.PriceHistory(ctx, 131, nepse.DateRange{From: day, To: nepse.NewDate(2025, 12, 31)})
+	if err != nil {
+		t.Fatalf("PriceHistory failed: %v", err)
+	}
+	if len(history) != 1096 {
+		t.Fatalf("expected 1096 rows, got %d", len(history))
+	}
+
+	history, err = client.PriceHistoryRange(ctx, 131, nepse.DateRange{From: day, To: day.AddDays(3 * 365)},
+		&nepse.PriceHistoryOptions{ChunkDays: 90, Concurrency: 3, PageSize: 40})
+	if err != nil {
+		t.Fatalf("PriceHistoryRange failed: %v", err)
+	}
+	if len(history) != 1096 {
+		t.Fatalf("expected 1096 rows, got %d", len(history))
+	}
+	for i, h := range history {
+		if h.ClosePrice != float64(i) {
+			t.Fatalf("row %d is %s; want sorted by date without duplicates", i, h.BusinessDate)
+		}
+	}
+}
+
+func TestServer_Faults(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expired token is refreshed", func(t *testing.T) {
+		srv := NewServer(DefaultScenario())
+		defer srv.Close()
+		client := newClient(t, srv)
+
+		if _, err := client.MarketStatus(ctx); err != nil {
+			t.Fatalf("MarketStatus failed: %v", err)
+		}
+		srv.ExpireTokens()
+		if _, err := client.MarketStatus(ctx); err != nil {
+			t.Fatalf("MarketStatus after token expiry failed: %v", err)
+		}
+		if hits := srv.Hits(tokenPath); hits != 1 {
+			t.Errorf("expected 1 prove request, got %d", hits)
+		}
+		if hits := srv.Hits(refreshPath); hits != 1 {
+			t.Errorf("expected 1 refresh request, got %d", hits)
+		}
+	})
+
+	t.Run("rejected refresh token falls back to prove", func(t *testing.T) {
+		srv := NewServer(DefaultScenario())
+		defer srv.Close()
+		client := newClient(t, srv)
+
+		if _, err := client.MarketStatus(ctx); err != nil {
+			t.Fatalf("MarketStatus failed: %v", err)
+		}
+		srv.ExpireTokens()
+		srv.RevokeRefreshTokens()
+		if _, err := client.MarketStatus(ctx); err != nil {
+			t.Fatalf("MarketStatus after revocation failed: %v", err)
+		}
+		if hits := srv.Hits(tokenPath); hits != 2 {
+			t.Errorf("expected 2 prove requests, got %d", hits)
+		}
+		if s := client.TokenStats(); s.Proves != 2 || s.Renewals != 0 {
+			t.Errorf("TokenStats() = %+v, want 2 proves", s)
+		}
+	})
+
+	t.Run("unserved refresh endpoint is not retried", func(t *testing.T) {
+		srv := NewServer(DefaultScenario())
+		defer srv.Close()
+		srv.Inject(Fault{Path: refreshPath, Status: http.StatusNotFound})
+		client := newClient(t, srv)
+
+		if _, err := client.MarketStatus(ctx); err != nil {
+			t.Fatalf("MarketStatus failed: %v", err)
+		}
+		for i := range 3 {
+			srv.ExpireTokens()
+			if _, err := client.MarketStatus(ctx); err != nil {
+				t.Fatalf("MarketStatus after expiry %d failed: %v", i, err)
+			}
+		}
+		// The first refresh tries the endpoint once; later refreshes only prove.
+		if hits := srv.Hits(refreshPath); hits != 1 {
+			t.Errorf("expected 1 refresh request, got %d", hits)
+		}
+		if hits := srv.Hits(tokenPath); hits != 4 {
+			t.Errorf("expected 4 prove requests, got %d", hits)
+		}
+	})
+
+	t.Run("server errors are retried", func(t *testing.T) {
+		srv := NewServer(DefaultScenario())
+		defer srv.Close()
+		client := newClient(t, srv)
+
+		srv.Inject(Fault{Path: "/api/nots/market-summary", Status: http.StatusBadGateway, Times: 2})
+		if _, err := client.MarketSummary(ctx); err != nil {
+			t.Fatalf("MarketSummary failed: %v", err)
+		}
+		if hits := srv.Hits("/api/nots/market-summary"); hits != 3 {
+			t.Errorf("expected 3 attempts, got %d", hits)
+		}
+	})
+
+	t.Run("rate limit surfaces", func(t *testing.T) {
+		srv := NewServer(DefaultScenario())
+		defer srv.Close()
+		client := newClient(t, srv, func(o *nepse.Options) { o.MaxRetries = 0 })
+
+		srv.Inject(Fault{Path: "/api/nots/top-ten", Status: http.StatusTooManyRequests, RetryAfter: "1"})
+		if _, err := client.TopGainers(ctx); !errors.Is(err, nepse.ErrRateLimit) {
+			t.Errorf("expected ErrRateLimit, got %v", err)
+		}
+
+		srv.ClearFaults()
+		if _, err := client.TopGainers(ctx); err != nil {
+			t.Errorf("TopGainers after ClearFaults failed: %v", err)
+		}
+	})
+
+	t.Run("forbidden endpoint", func(t *testing.T) {
+		srv := NewServer(DefaultScenario())
+		defer srv.Close()
+		client := newClient(t, srv)
+
+		srv.Inject(Fault{Path: "/api/nots/security/floorsheet", Status: http.StatusForbidden})
+		_, err := client.FloorSheetOf(ctx, 131, nepse.NewDate(2026, 1, 2))
+		if !errors.Is(err, nepse.ErrEndpointBlocked) || !errors.Is(err, nepse.ErrUnauthorized) {
+			t.Errorf("expected ErrEndpointBlocked wrapping ErrUnauthorized, got %v", err)
+		}
+	})
+}
+
+func TestServer_ResponsesOverride(t *testing.T) {
+	sc := DefaultScenario()
+	sc.Responses = map[string]any{
+		"/api/nots/nepse-data/marketdepth/131": map[string]any{"totalBuyQty": 42},
+	}
+	srv := NewServer(sc)
+	defer srv.Close()
+	client := newClient(t, srv)
+
+	depth, err := client.MarketDepth(context.Background(), 131)
+	if err != nil {
+		t.Fatalf("MarketDepth failed: %v", err)
+	}
+	if depth.TotalBuyQty != 42 {
+		t.Errorf("TotalBuyQty = %d, want 42", depth.TotalBuyQty)
+	}
+
+	srv.Update(func(sc *Scenario) { sc.MarketStatus.IsOpen = "CLOSE" })
+	status, err := client.MarketStatus(context.Background())
+	if err != nil {
+		t.Fatalf("MarketStatus failed: %v", err)
+	}
+	if status.IsMarketOpen() {
+		t.Error("expected market closed after Update")
+	}
+}
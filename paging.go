@@ -0,0 +1,148 @@
+package nepse
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// DefaultPageSize is the page size paged endpoints are read with when
+// [PageOptions.PageSize] is zero. NEPSE serves at most 500 rows per page.
+const DefaultPageSize = 500
+
+// PageOptions controls how paged endpoints are read by the Seq methods.
+// A nil *PageOptions uses the defaults.
+type PageOptions struct {
+	PageSize int // Rows per request; zero uses DefaultPageSize
+	Prefetch int // Pages fetched ahead of the consumer, concurrently; zero fetches one page at a time
+}
+
+func (o *PageOptions) pageSize() int {
+	if o == nil || o.PageSize <= 0 {
+		return DefaultPageSize
+	}
+	return o.PageSize
+}
+
+func (o *PageOptions) prefetch() int {
+	if o == nil || o.Prefetch < 0 {
+		return 0
+	}
+	return o.Prefetch
+}
+
+// pageFetcher fetches one page, numbered from zero.
+type pageFetcher[T any] func(ctx context.Context, page, size int) (*PaginatedResponse[T], error)
+
+// pageResult is a fetched page or the error fetching it.
+type pageResult[T any] struct {
+	page *PaginatedResponse[T]
+	err  error
+}
+
+// paginate yields every row of a paged endpoint in order. The first page is
+// fetched alone to learn the page count; after that up to opts.Prefetch
+// pages are fetched while the consumer works through the current one.
+// Iteration ends after the first error, which is yielded with a zero row.
+// Stopping early cancels outstanding fetches and waits for them to return.
+func paginate[T any](ctx context.Context, opts *PageOptions, fetch pageFetcher[T]) iter.Seq2[T, error] {
+	size, prefetch := opts.pageSize(), opts.prefetch()
+
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer cancel()
+
+		var zero T
+		first, err := fetch(ctx, 0, size)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		for _, row := range first.Content {
+			if !yield(row, nil) {
+				return
+			}
+		}
+
+		// pending holds the pages in flight, in page order.
+		total := int(first.TotalPages)
+		pending := make([]chan pageResult[T], 0, prefetch+1)
+		next := 1
+		for next < total || len(pending) > 0 {
+			for next < total && len(pending) <= prefetch {
+				ch := make(chan pageResult[T], 1)
+				page := next
+				wg.Go(func() {
+					resp, err := fetch(ctx, page, size)
+					ch <- pageResult[T]{resp, err}
+				})
+				pending = append(pending, ch)
+				next++
+			}
+
+			res := <-pending[0]
+			pending = pending[1:]
+			if res.err != nil {
+				yield(zero, res.err)
+				return
+			}
+			for _, row := range res.page.Content {
+				if !yield(row, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Defaults for [PriceHistoryOptions].
+const (
+	DefaultPriceHistoryChunkDays   = 365
+	DefaultPriceHistoryConcurrency = 4
+)
+
+// PriceHistoryOptions controls how [Client.PriceHistoryRange] splits a date
+// range. A nil *PriceHistoryOptions uses the defaults.
+type PriceHistoryOptions struct {
+	ChunkDays   int // Days per request chunk; zero uses DefaultPriceHistoryChunkDays
+	Concurrency int // Chunks fetched at once; zero uses DefaultPriceHistoryConcurrency
+	PageSize    int // Rows per page within a chunk; zero uses DefaultPageSize
+}
+
+func (o *PriceHistoryOptions) chunkDays() int {
+	if o == nil || o.ChunkDays <= 0 {
+		return DefaultPriceHistoryChunkDays
+	}
+	return o.ChunkDays
+}
+
+func (o *PriceHistoryOptions) concurrency() int {
+	if o == nil || o.Concurrency <= 0 {
+		return DefaultPriceHistoryConcurrency
+	}
+	return o.Concurrency
+}
+
+func (o *PriceHistoryOptions) pageSize() int {
+	if o == nil {
+		return 0
+	}
+	return o.PageSize
+}
+
+// dateChunks splits r into consecutive ranges of at most days days. It
+// returns nil when r.From is after r.To.
+func dateChunks(r DateRange, days int) []DateRange {
+	var chunks []DateRange
+	for from := r.From; !from.After(r.To); {
+		last := from.AddDays(days - 1)
+		if last.After(r.To) {
+			last = r.To
+		}
+		chunks = append(chunks, DateRange{From: from, To: last})
+		from = last.AddDays(1)
+	}
+	return chunks
+}
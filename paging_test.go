@@ -0,0 +1,203 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pagedRows serves rows 0..total-1 in pages, tracking concurrent fetches.
+type pagedRows struct {
+	total    int
+	failPage int // page that returns an error; zero for none
+	inFlight atomic.Int32
+	maxAhead atomic.Int32
+	fetches  atomic.Int32
+}
+
+func (p *pagedRows) fetch(ctx context.Context, page, size int) (*PaginatedResponse[int], error) {
+	p.fetches.Add(1)
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		m := p.maxAhead.Load()
+		if n <= m || p.maxAhead.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	// Give prefetches a chance to overlap.
+	select {
+	case <-time.After(time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if page != 0 && page == p.failPage {
+		return nil, NewInternalError("page failed", nil)
+	}
+	resp := &PaginatedResponse[int]{TotalPages: int32((p.total + size - 1) / size)}
+	for i := page * size; i < min((page+1)*size, p.total); i++ {
+		resp.Content = append(resp.Content, i)
+	}
+	return resp, nil
+}
+
+func TestPaginate_Order(t *testing.T) {
+	for _, prefetch := range []int{0, 1, 5} {
+		rows := &pagedRows{total: 103}
+		var got []int
+		for v, err := range paginate(context.Background(), &PageOptions{PageSize: 10, Prefetch: prefetch}, rows.fetch) {
+			if err != nil {
+				t.Fatalf("prefetch %d: %v", prefetch, err)
+			}
+			got = append(got, v)
+		}
+		if len(got) != 103 {
+			t.Fatalf("prefetch %d: got %d rows, want 103", prefetch, len(got))
+		}
+		for i, v := range got {
+			if v != i {
+				t.Fatalf("prefetch %d: row %d = %d", prefetch, i, v)
+			}
+		}
+		if peak := int(rows.maxAhead.Load()); peak > prefetch+1 {
+			t.Errorf("prefetch %d: %d fetches in flight, want at most %d", prefetch, peak, prefetch+1)
+		}
+	}
+}
+
+func TestPaginate_Error(t *testing.T) {
+	rows := &pagedRows{total: 100, failPage: 3}
+	var n, errs int
+	for _, err := range paginate(context.Background(), &PageOptions{PageSize: 10, Prefetch: 2}, rows.fetch) {
+		if err != nil {
+			errs++
+			if !errors.Is(err, ErrInternal) {
+				t.Errorf("err = %v, want ErrInternal", err)
+			}
+			continue
+		}
+		n++
+	}
+	if n != 30 || errs != 1 {
+		t.Errorf("got %d rows and %d errors, want 30 rows then 1 error", n, errs)
+	}
+}
+
+func TestPaginate_StopEarly(t *testing.T) {
+	rows := &pagedRows{total: 1000}
+	for v := range paginate(context.Background(), &PageOptions{PageSize: 10, Prefetch: 3}, rows.fetch) {
+		if v == 25 {
+			break
+		}
+	}
+	if n := rows.inFlight.Load(); n != 0 {
+		t.Errorf("%d fetches still running after the loop ended", n)
+	}
+	// Pages 0-2 were read, and at most 3 more were fetched ahead.
+	if n := rows.fetches.Load(); n > 6 {
+		t.Errorf("%d pages fetched, want at most 6", n)
+	}
+}
+
+func TestPageOptions_Defaults(t *testing.T) {
+	var nilOpts *PageOptions
+	if nilOpts.pageSize() != DefaultPageSize || nilOpts.prefetch() != 0 {
+		t.Error("nil PageOptions should use the defaults")
+	}
+	opts := &PageOptions{PageSize: -1, Prefetch: -3}
+	if opts.pageSize() != DefaultPageSize || opts.prefetch() != 0 {
+		t.Error("negative PageOptions should use the defaults")
+	}
+}
+
+func TestDateChunks(t *testing.T) {
+	day := func(s string) Date {
+		d, err := ParseDate(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	span := func(from, to string) DateRange { return DateRange{From: day(from), To: day(to)} }
+	tests := []struct {
+		name string
+		r    DateRange
+		days int
+		want []DateRange
+	}{
+		{"single day", span("2026-01-01", "2026-01-01"), 30, []DateRange{span("2026-01-01", "2026-01-01")}},
+		{"exact chunks", span("2026-01-01", "2026-01-06"), 3, []DateRange{span("2026-01-01", "2026-01-03"), span("2026-01-04", "2026-01-06")}},
+		{"short tail", span("2024-02-27", "2024-03-02"), 3, []DateRange{span("2024-02-27", "2024-02-29"), span("2024-03-01", "2024-03-02")}},
+		{"reversed", span("2026-01-02", "2026-01-01"), 3, nil},
+		{
+			"dates taken in NPT",
+			DateRange{
+				From: DateOf(time.Date(2025, 12, 31, 18, 30, 0, 0, time.UTC)),
+				To:   DateOf(time.Date(2026, 1, 2, 1, 0, 0, 0, time.UTC)),
+			},
+			10,
+			[]DateRange{span("2026-01-01", "2026-01-02")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dateChunks(tt.r, tt.days)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("dateChunks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_PriceHistoryRangeConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	client := newRequestTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := peak.Load()
+			if n <= m || peak.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		// Every chunk returns its first day, and also the first chunk's,
+		// which must be deduplicated.
+		first, _ := ParseDate(r.URL.Query().Get("startDate"))
+		json.NewEncoder(w).Encode(PaginatedResponse[PriceHistory]{
+			Content: []PriceHistory{
+				{BusinessDate: first},
+				{BusinessDate: NewDate(2026, 1, 1)},
+			},
+			TotalPages: 1,
+		})
+	})
+
+	start := NewDate(2026, 1, 1)
+	history, err := client.PriceHistoryRange(context.Background(), 131, DateRange{From: start, To: start.AddDays(99)},
+		&PriceHistoryOptions{ChunkDays: 10, Concurrency: 3})
+	if err != nil {
+		t.Fatalf("PriceHistoryRange failed: %v", err)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("%d chunks fetched at once, want at most 3", p)
+	}
+	if len(history) != 10 {
+		t.Fatalf("got %d rows, want one per chunk: %+v", len(history), history)
+	}
+	if !slices.IsSortedFunc(history, func(a, b PriceHistory) int { return a.BusinessDate.Compare(b.BusinessDate) }) {
+		t.Errorf("history not sorted: %+v", history)
+	}
+
+	_, err = client.PriceHistoryRange(context.Background(), 131, DateRange{From: start.AddDays(1), To: start}, nil)
+	if !errors.Is(err, ErrInvalidClientRequest) {
+		t.Errorf("reversed range error = %v, want ErrInvalidClientRequest", err)
+	}
+}
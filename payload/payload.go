@@ -0,0 +1,160 @@
+// Package payload computes the POST payload IDs NEPSE requires for graph and
+// security-detail endpoints. It is shared by the client and the fake server in
+// nepsetest so both sides always agree on the algorithm.
+//
+// The functions are pure: given the dummyData table, the market status ID,
+// the day of month in Nepal Time, and for index graphs the token salts, they
+// return the id NEPSE expects in the POST body. testdata/vectors.json holds
+// known inputs and outputs for checking ports of the algorithm to other
+// languages, and cmd/nepse-payload prints payloads for given inputs.
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// NPT is Nepal Time: Asia/Kathmandu from the tz database, or a fixed +05:45
+// zone when the database is unavailable. Nepal has kept +05:45 without
+// daylight saving since 1986, so the two agree for every date NEPSE has
+// traded and payload IDs do not depend on whether tzdata is installed.
+var NPT = loadNPT()
+
+func loadNPT() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Kathmandu"); err == nil {
+		return loc
+	}
+	return time.FixedZone("NPT", 5*60*60+45*60)
+}
+
+// Day returns the day of month at t in Nepal, which is what NEPSE expects.
+func Day(t time.Time) int {
+	return t.In(NPT).Day()
+}
+
+// Salts holds the 5 salt values from a NEPSE token response. Only Salt1
+// through Salt4 affect payload IDs.
+type Salts struct {
+	Salt1 int
+	Salt2 int
+	Salt3 int
+	Salt4 int
+	Salt5 int
+}
+
+// Table is the static array NEPSE's obfuscation algorithm indexes with the
+// market status ID. NEPSE ships it in its browser bundle as dummyData.
+type Table [100]int
+
+// dummyData is the table NEPSE currently serves.
+var dummyData = Table{
+	147, 117, 239, 143, 157, 312, 161, 612, 512, 804,
+	411, 527, 170, 511, 421, 667, 764, 621, 301, 106,
+	133, 793, 411, 511, 312, 423, 344, 346, 653, 758,
+	342, 222, 236, 811, 711, 611, 122, 447, 128, 199,
+	183, 135, 489, 703, 800, 745, 152, 863, 134, 211,
+	142, 564, 375, 793, 212, 153, 138, 153, 648, 611,
+	151, 649, 318, 143, 117, 756, 119, 141, 717, 113,
+	112, 146, 162, 660, 693, 261, 362, 354, 251, 641,
+	157, 178, 631, 192, 734, 445, 192, 883, 187, 122,
+	591, 731, 852, 384, 565, 596, 451, 772, 624, 691,
+}
+
+// DefaultTable returns a copy of the built-in table.
+func DefaultTable() *Table {
+	t := dummyData
+	return &t
+}
+
+// ParseTable reads a replacement table encoded as a JSON array of exactly
+// 100 integers, the same literal NEPSE's browser bundle declares.
+func ParseTable(r io.Reader) (*Table, error) {
+	var values []int
+	if err := json.NewDecoder(r).Decode(&values); err != nil {
+		return nil, fmt.Errorf("decode payload table: %w", err)
+	}
+	var t Table
+	if len(values) != len(t) {
+		return nil, fmt.Errorf("payload table has %d entries, want %d", len(values), len(t))
+	}
+	copy(t[:], values)
+	return &t, nil
+}
+
+// Base computes the base payload value from the market status ID and the
+// current day of month in Nepal time: table[id] + id + 2 * day.
+// Out-of-range IDs wrap around the table.
+func (t *Table) Base(statusID, day int) int {
+	id := statusID % len(t)
+	if id < 0 {
+		id += len(t)
+	}
+	return t[id] + id + 2*day
+}
+
+// Base computes the base payload value with the built-in table.
+func Base(statusID, day int) int {
+	return dummyData.Base(statusID, day)
+}
+
+// Vector is a known input and output of [Table.Base].
+type Vector struct {
+	StatusID int
+	Day      int
+	Base     int
+}
+
+// DefaultVectors returns vectors that hold for the built-in table.
+func DefaultVectors() []Vector {
+	return []Vector{
+		{StatusID: 0, Day: 15, Base: 177},
+		{StatusID: 42, Day: 10, Base: 551},
+		{StatusID: 61, Day: 1, Base: 712},
+		{StatusID: 99, Day: 31, Base: 852},
+	}
+}
+
+// Verify checks every vector against the table and reports the first that
+// does not hold.
+func (t *Table) Verify(vectors []Vector) error {
+	for i, v := range vectors {
+		if got := t.Base(v.StatusID, v.Day); got != v.Base {
+			return fmt.Errorf("payload vector %d: status %d day %d gave base %d, want %d",
+				i, v.StatusID, v.Day, got, v.Base)
+		}
+	}
+	return nil
+}
+
+// ScripGraph computes the payload ID for security graph and security-detail
+// endpoints, which is the base value itself.
+func (t *Table) ScripGraph(statusID, day int) int {
+	return t.Base(statusID, day)
+}
+
+// IndexGraph computes the payload ID for index graph endpoints.
+func (t *Table) IndexGraph(statusID, day int, salts Salts) int {
+	return Index(t.Base(statusID, day), day, salts)
+}
+
+// ScripGraph computes the security graph payload ID with the built-in table.
+func ScripGraph(statusID, day int) int {
+	return dummyData.ScripGraph(statusID, day)
+}
+
+// IndexGraph computes the index graph payload ID with the built-in table.
+func IndexGraph(statusID, day int, salts Salts) int {
+	return dummyData.IndexGraph(statusID, day, salts)
+}
+
+// Index computes the payload ID for index graph endpoints from the base value.
+// Logic: if (base % 10 < 5) use salts[3] * day - salts[2], else use salts[1] * day - salts[0].
+// Python uses a 1-indexed array, so: salts[3] = Salt4, salts[1] = Salt2, salts[2] = Salt3, salts[0] = Salt1.
+func Index(base, day int, salts Salts) int {
+	if base%10 < 5 {
+		return base + salts.Salt4*day - salts.Salt3
+	}
+	return base + salts.Salt2*day - salts.Salt1
+}
@@ -0,0 +1,115 @@
+package payload
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// vectorFile mirrors testdata/vectors.json, which other ports of the
+// algorithm check themselves against.
+type vectorFile struct {
+	Vectors []struct {
+		StatusID   int    `json:"statusId"`
+		Day        int    `json:"day"`
+		Salts      [5]int `json:"salts"`
+		Base       int    `json:"base"`
+		ScripGraph int    `json:"scripGraph"`
+		IndexGraph int    `json:"indexGraph"`
+	} `json:"vectors"`
+}
+
+func TestVectorFile(t *testing.T) {
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file vectorFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("decode vectors: %v", err)
+	}
+	if len(file.Vectors) == 0 {
+		t.Fatal("no vectors")
+	}
+
+	var branches [2]bool
+	for _, v := range file.Vectors {
+		salts := Salts{Salt1: v.Salts[0], Salt2: v.Salts[1], Salt3: v.Salts[2], Salt4: v.Salts[3], Salt5: v.Salts[4]}
+		if got := Base(v.StatusID, v.Day); got != v.Base {
+			t.Errorf("Base(%d, %d) = %d, want %d", v.StatusID, v.Day, got, v.Base)
+		}
+		if got := ScripGraph(v.StatusID, v.Day); got != v.ScripGraph {
+			t.Errorf("ScripGraph(%d, %d) = %d, want %d", v.StatusID, v.Day, got, v.ScripGraph)
+		}
+		if got := IndexGraph(v.StatusID, v.Day, salts); got != v.IndexGraph {
+			t.Errorf("IndexGraph(%d, %d, %v) = %d, want %d", v.StatusID, v.Day, v.Salts, got, v.IndexGraph)
+		}
+		branches[min(v.Base%10/5, 1)] = true
+	}
+	if !branches[0] || !branches[1] {
+		t.Error("vectors must cover both index graph branches (base%10 < 5 and >= 5)")
+	}
+}
+
+func TestDefaultVectors(t *testing.T) {
+	if err := DefaultTable().Verify(DefaultVectors()); err != nil {
+		t.Fatal(err)
+	}
+
+	table := DefaultTable()
+	table[61]++
+	if err := table.Verify(DefaultVectors()); err == nil {
+		t.Fatal("expected edited table to fail the default vectors")
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"valid", "[" + strings.Repeat("1,", 99) + "1]", ""},
+		{"short", "[1,2,3]", "has 3 entries"},
+		{"not json", "dummyData", "decode payload table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := ParseTable(strings.NewReader(tt.input))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParseTable failed: %v", err)
+				}
+				if got := table.Base(5, 1); got != 1+5+2 {
+					t.Errorf("Base(5, 1) = %d, want 8", got)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseTable error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDay(t *testing.T) {
+	tests := []struct {
+		utc  string
+		want int
+	}{
+		{"2026-01-01T18:14:00Z", 1}, // 23:59 NPT
+		{"2026-01-01T18:15:00Z", 2}, // midnight NPT
+		{"2026-01-31T23:00:00Z", 1}, // already February in Nepal
+	}
+	for _, tt := range tests {
+		at, err := time.Parse(time.RFC3339, tt.utc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Day(at); got != tt.want {
+			t.Errorf("Day(%s) = %d, want %d", tt.utc, got, tt.want)
+		}
+	}
+}
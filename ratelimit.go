@@ -0,0 +1,103 @@
+package nepse
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket shared by every request a client makes,
+// including token acquisition. A 429 from any request pauses all callers
+// until the pause expires, so concurrent goroutines back off together.
+type rateLimiter struct {
+	rate  float64 // tokens per second; <= 0 disables limiting
+	burst float64
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until the caller may send a request or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+
+		if now.Before(l.pausedUntil) {
+			delay := l.pausedUntil.Sub(now)
+			l.mu.Unlock()
+			if err := sleepCtx(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if l.rate <= 0 {
+			l.mu.Unlock()
+			return nil
+		}
+
+		// Reserve a token; a negative balance is the caller's place in line.
+		l.refill(now)
+		l.tokens--
+		if l.tokens >= 0 {
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := sleepCtx(ctx, delay); err != nil {
+			l.mu.Lock()
+			l.tokens++
+			l.mu.Unlock()
+			return err
+		}
+		return nil
+	}
+}
+
+// refill adds tokens accrued since the last refill. Caller must hold l.mu.
+func (l *rateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	l.tokens = min(l.tokens+elapsed*l.rate, l.burst)
+}
+
+// pause stops all callers from sending requests for d.
+func (l *rateLimiter) pause(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// sleepCtx waits for d or until ctx is done, whichever comes first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,204 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_BurstThenRate(t *testing.T) {
+	l := newRateLimiter(50, 3) // one token every 20ms after a burst of 3
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("burst should not block, took %v", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected ~60ms of throttling after burst, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	l := newRateLimiter(0, 0)
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatalf("wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("unlimited limiter should not block, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_PauseBlocksAllCallers(t *testing.T) {
+	l := newRateLimiter(0, 0)
+	l.pause(50 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	elapsed := make([]time.Duration, 5)
+	start := time.Now()
+	for i := range elapsed {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := l.wait(context.Background()); err != nil {
+				t.Errorf("wait failed: %v", err)
+			}
+			elapsed[i] = time.Since(start)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, d := range elapsed {
+		if d < 40*time.Millisecond {
+			t.Errorf("goroutine %d proceeded after %v, expected to wait for pause", i, d)
+		}
+	}
+}
+
+func TestRateLimiter_ContextCancellation(t *testing.T) {
+	l := newRateLimiter(1, 1)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("wait failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestClient_RateLimitSharedAcrossRequests(t *testing.T) {
+	var callCount atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tokenResponse())
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewClient(&Options{
+		BaseURL:     server.URL,
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  0,
+		RateLimit:   50,
+		RateBurst:   1,
+		Config: &Config{
+			BaseURL: server.URL,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Token(context.Background()); err != nil {
+				t.Errorf("Token() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("expected 5 requests at 50 rps to take ~80ms, took %v", elapsed)
+	}
+	if callCount.Load() != 5 {
+		t.Errorf("expected 5 calls, got %d", callCount.Load())
+	}
+}
+
+func TestClient_RateLimitPausesOn429(t *testing.T) {
+	var callCount atomic.Int32
+	var mu sync.Mutex
+	var first time.Time
+	var later []time.Time
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if callCount.Add(1) == 1 {
+			first = time.Now()
+			mu.Unlock()
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		later = append(later, time.Now())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tokenResponse())
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewClient(&Options{
+		BaseURL:     server.URL,
+		HTTPTimeout: 5 * time.Second,
+		RetryPolicy: RetryPolicyFunc(func(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+			return 60 * time.Millisecond, attempt == 1
+		}),
+		Config: &Config{
+			BaseURL: server.URL,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if _, err := client.Token(ctx); err != nil {
+			t.Errorf("Token() failed: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		// Start after the first request has been rate limited.
+		time.Sleep(20 * time.Millisecond)
+		if _, err := client.Token(ctx); err != nil {
+			t.Errorf("Token() failed: %v", err)
+		}
+	}()
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(later) != 2 {
+		t.Fatalf("expected 2 successful calls, got %d", len(later))
+	}
+	for i, at := range later {
+		if gap := at.Sub(first); gap < 50*time.Millisecond {
+			t.Errorf("call %d sent %v after the 429, expected all goroutines to pause", i, gap)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package nepse
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultRegistryTTL is how long the security registry serves its cached
+// lists before reloading them. Listings change a few times a month at most.
+const DefaultRegistryTTL = 6 * time.Hour
+
+// SecurityRegistry caches the security and company lists and indexes them by
+// ID, symbol, and ISIN. All *BySymbol methods resolve through it, so looping
+// over many symbols costs one list download instead of one per call.
+// Use [Client.Registry] to access the client's registry.
+type SecurityRegistry struct {
+	client *Client
+	ttl    time.Duration
+
+	mu       sync.RWMutex
+	byID     map[int32]*registryEntry
+	bySymbol map[string]*registryEntry
+	byISIN   map[string]*registryEntry
+	loadedAt time.Time
+
+	sf singleflight.Group
+}
+
+// registryEntry joins a security with its company listing, if any.
+type registryEntry struct {
+	security Security
+	company  *Company
+}
+
+func newSecurityRegistry(c *Client, ttl time.Duration) *SecurityRegistry {
+	if ttl <= 0 {
+		ttl = DefaultRegistryTTL
+	}
+	return &SecurityRegistry{client: c, ttl: ttl}
+}
+
+// Registry returns the client's security registry.
+func (c *Client) Registry() *SecurityRegistry {
+	return c.registry
+}
+
+// Security returns the security with the given ID.
+func (r *SecurityRegistry) Security(ctx context.Context, id int32) (*Security, error) {
+	if id <= 0 {
+		return nil, NewInvalidClientRequestError("security ID must be positive")
+	}
+	if err := r.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if e, ok := r.byID[id]; ok {
+		s := e.security
+		return &s, nil
+	}
+	return nil, NewNotFoundError(fmt.Sprintf("security with ID %d", id))
+}
+
+// SecurityBySymbol returns the security with the given ticker symbol.
+// The symbol is matched case-insensitively.
+func (r *SecurityRegistry) SecurityBySymbol(ctx context.Context, symbol string) (*Security, error) {
+	symbol = normalizeSymbol(symbol)
+	if symbol == "" {
+		return nil, NewInvalidClientRequestError("symbol cannot be empty")
+	}
+	if err := r.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if e, ok := r.bySymbol[symbol]; ok {
+		s := e.security
+		return &s, nil
+	}
+	return nil, NewNotFoundError("security with symbol " + symbol)
+}
+
+// SecurityByISIN returns the security with the given ISIN.
+// ISINs are known for securities whose list entry includes one, and for any
+// security fetched through [Client.SecurityDetail].
+func (r *SecurityRegistry) SecurityByISIN(ctx context.Context, isin string) (*Security, error) {
+	isin = strings.ToUpper(strings.TrimSpace(isin))
+	if isin == "" {
+		return nil, NewInvalidClientRequestError("ISIN cannot be empty")
+	}
+	if err := r.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if e, ok := r.byISIN[isin]; ok {
+		s := e.security
+		return &s, nil
+	}
+	return nil, NewNotFoundError("security with ISIN " + isin)
+}
+
+// CompanyBySymbol returns the company listing (sector, instrument type, etc.)
+// for the given ticker symbol.
+func (r *SecurityRegistry) CompanyBySymbol(ctx context.Context, symbol string) (*Company, error) {
+	symbol = normalizeSymbol(symbol)
+	if symbol == "" {
+		return nil, NewInvalidClientRequestError("symbol cannot be empty")
+	}
+	if err := r.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if e, ok := r.bySymbol[symbol]; ok && e.company != nil {
+		c := *e.company
+		return &c, nil
+	}
+	return nil, NewNotFoundError("company with symbol " + symbol)
+}
+
+// Refresh reloads the security and company lists regardless of their age.
+func (r *SecurityRegistry) Refresh(ctx context.Context) error {
+	return r.load(ctx, true)
+}
+
+// Invalidate marks the cached lists as stale so the next lookup reloads them.
+func (r *SecurityRegistry) Invalidate() {
+	r.mu.Lock()
+	r.loadedAt = time.Time{}
+	r.mu.Unlock()
+}
+
+// LoadedAt returns when the lists were last loaded, or the zero time if never.
+func (r *SecurityRegistry) LoadedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.loadedAt
+}
+
+// ensureLoaded loads the lists if they are missing or older than the TTL.
+// If a reload fails but earlier data exists, the earlier data keeps being served.
+func (r *SecurityRegistry) ensureLoaded(ctx context.Context) error {
+	if r.isFresh() {
+		return nil
+	}
+	err := r.load(ctx, false)
+	if err != nil && r.hasData() {
+		return nil
+	}
+	return err
+}
+
+func (r *SecurityRegistry) isFresh() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !r.loadedAt.IsZero() && time.Since(r.loadedAt) < r.ttl
+}
+
+func (r *SecurityRegistry) hasData() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.byID != nil
+}
+
+// registryLoadTimeout bounds a shared list load. The load runs detached from
+// the callers' contexts so that one caller giving up does not fail the others.
+const registryLoadTimeout = 2 * time.Minute
+
+// load fetches both lists once for all concurrent callers. Each caller waits
+// only as long as its own ctx allows.
+func (r *SecurityRegistry) load(ctx context.Context, force bool) error {
+	ch := r.sf.DoChan("registry_load", func() (any, error) {
+		if !force && r.isFresh() {
+			return nil, nil
+		}
+		lctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), registryLoadTimeout)
+		defer cancel()
+
+		var (
+			securities []Security
+			companies  []Company
+			companyErr error
+		)
+		g, gctx := errgroup.WithContext(lctx)
+		g.Go(func() error {
+			var err error
+			securities, err = r.client.Securities(gctx)
+			return err
+		})
+		g.Go(func() error {
+			// Company listings only enrich the securities, so a failure here
+			// must not block symbol lookups.
+			companies, companyErr = r.client.Companies(gctx)
+			return nil
+		})
+		if err := g.Wait(); err != nil {
+			return nil, err
+		}
+		if companyErr != nil {
+			r.client.logger.WarnContext(lctx, "nepse company list unavailable, indexing securities only",
+				slog.Any("error", companyErr))
+		}
+
+		r.index(securities, companies, companyErr == nil)
+		return nil, nil
+	})
+
+	select {
+	case res := <-ch:
+		return res.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// index rebuilds the lookup maps. ISINs learned since the last load are kept
+// for securities that are still listed, as are company listings when
+// haveCompanies is false.
+func (r *SecurityRegistry) index(securities []Security, companies []Company, haveCompanies bool) {
+	companyBySymbol := make(map[string]*Company, len(companies))
+	for i := range companies {
+		companyBySymbol[normalizeSymbol(companies[i].Symbol)] = &companies[i]
+	}
+
+	byID := make(map[int32]*registryEntry, len(securities))
+	bySymbol := make(map[string]*registryEntry, len(securities))
+	byISIN := make(map[string]*registryEntry)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range securities {
+		sym := normalizeSymbol(s.Symbol)
+		if s.ISIN == "" {
+			if old, ok := r.byID[s.ID]; ok {
+				s.ISIN = old.security.ISIN
+			}
+		}
+		e := &registryEntry{security: s, company: companyBySymbol[sym]}
+		if !haveCompanies {
+			if old, ok := r.byID[s.ID]; ok {
+				e.company = old.company
+			}
+		}
+		byID[s.ID] = e
+		bySymbol[sym] = e
+		if s.ISIN != "" {
+			byISIN[strings.ToUpper(s.ISIN)] = e
+		}
+	}
+
+	r.byID = byID
+	r.bySymbol = bySymbol
+	r.byISIN = byISIN
+	r.loadedAt = time.Now()
+}
+
+// observeISIN records an ISIN seen in another response so that
+// [SecurityRegistry.SecurityByISIN] can resolve it.
+func (r *SecurityRegistry) observeISIN(id int32, isin string) {
+	isin = strings.ToUpper(strings.TrimSpace(isin))
+	if isin == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.byID[id]; ok {
+		e.security.ISIN = isin
+		r.byISIN[isin] = e
+	}
+}
+
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
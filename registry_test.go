@@ -0,0 +1,235 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRegistryTestServer serves a small security and company list and counts
+// how often each list is fetched.
+func newRegistryTestServer(securityCalls, companyCalls *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(serveToken(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/nots/security":
+			if r.URL.Query().Get("nonDelisted") == "true" {
+				securityCalls.Add(1)
+				json.NewEncoder(w).Encode([]Security{
+					{ID: 131, Symbol: "NABIL", SecurityName: "Nabil Bank Limited", ActiveStatus: "A"},
+					{ID: 2790, Symbol: "UPPER", SecurityName: "Upper Tamakoshi Hydropower Ltd", ActiveStatus: "A"},
+				})
+				return
+			}
+			http.NotFound(w, r)
+		case "/api/nots/company/list":
+			companyCalls.Add(1)
+			json.NewEncoder(w).Encode([]Company{
+				{ID: 131, Symbol: "NABIL", SectorName: SectorBanking},
+			})
+		case "/api/nots/security/profile/131":
+			json.NewEncoder(w).Encode(CompanyProfile{CompanyName: "Nabil Bank Limited"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestRegistry_BySymbolLoadsListsOnce(t *testing.T) {
+	var securityCalls, companyCalls atomic.Int32
+	server := newRegistryTestServer(&securityCalls, &companyCalls)
+	defer server.Close()
+
+	client := newTestClient(t, server, Options{})
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		profile, err := client.CompanyProfileBySymbol(ctx, "nabil")
+		if err != nil {
+			t.Fatalf("CompanyProfileBySymbol failed: %v", err)
+		}
+		if profile.CompanyName != "Nabil Bank Limited" {
+			t.Errorf("unexpected company name %q", profile.CompanyName)
+		}
+	}
+
+	if securityCalls.Load() != 1 {
+		t.Errorf("expected 1 security list fetch, got %d", securityCalls.Load())
+	}
+	if companyCalls.Load() != 1 {
+		t.Errorf("expected 1 company list fetch, got %d", companyCalls.Load())
+	}
+}
+
+func TestRegistry_Lookups(t *testing.T) {
+	var securityCalls, companyCalls atomic.Int32
+	server := newRegistryTestServer(&securityCalls, &companyCalls)
+	defer server.Close()
+
+	client := newTestClient(t, server, Options{})
+	reg := client.Registry()
+	ctx := context.Background()
+
+	sec, err := client.FindSecurity(ctx, 2790)
+	if err != nil {
+		t.Fatalf("FindSecurity failed: %v", err)
+	}
+	if sec.Symbol != "UPPER" {
+		t.Errorf("expected UPPER, got %q", sec.Symbol)
+	}
+
+	company, err := reg.CompanyBySymbol(ctx, "NABIL")
+	if err != nil {
+		t.Fatalf("CompanyBySymbol failed: %v", err)
+	}
+	if company.SectorName != SectorBanking {
+		t.Errorf("expected sector %q, got %q", SectorBanking, company.SectorName)
+	}
+
+	if _, err := reg.CompanyBySymbol(ctx, "UPPER"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for security without company listing, got %v", err)
+	}
+	if _, err := client.FindSecurityBySymbol(ctx, "MISSING"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := client.FindSecurityBySymbol(ctx, "  "); !errors.Is(err, ErrInvalidClientRequest) {
+		t.Errorf("expected ErrInvalidClientRequest, got %v", err)
+	}
+	if _, err := client.FindSecurity(ctx, 0); !errors.Is(err, ErrInvalidClientRequest) {
+		t.Errorf("expected ErrInvalidClientRequest, got %v", err)
+	}
+
+	if _, err := reg.SecurityByISIN(ctx, "NPE014A00007"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound before ISIN is known, got %v", err)
+	}
+	reg.observeISIN(131, "npe014a00007")
+	sec, err = reg.SecurityByISIN(ctx, "NPE014A00007")
+	if err != nil {
+		t.Fatalf("SecurityByISIN failed: %v", err)
+	}
+	if sec.Symbol != "NABIL" || sec.ISIN != "NPE014A00007" {
+		t.Errorf("unexpected security %+v", sec)
+	}
+
+	if securityCalls.Load() != 1 {
+		t.Errorf("expected 1 security list fetch, got %d", securityCalls.Load())
+	}
+}
+
+func TestRegistry_RefreshAndTTL(t *testing.T) {
+	var securityCalls, companyCalls atomic.Int32
+	server := newRegistryTestServer(&securityCalls, &companyCalls)
+	defer server.Close()
+
+	client := newTestClient(t, server, Options{RegistryTTL: 20 * time.Millisecond})
+	reg := client.Registry()
+	ctx := context.Background()
+
+	if _, err := reg.SecurityBySymbol(ctx, "NABIL"); err != nil {
+		t.Fatalf("SecurityBySymbol failed: %v", err)
+	}
+	reg.observeISIN(131, "NPE014A00007")
+
+	if err := reg.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if securityCalls.Load() != 2 {
+		t.Errorf("expected 2 security list fetches after Refresh, got %d", securityCalls.Load())
+	}
+	if _, err := reg.SecurityByISIN(ctx, "NPE014A00007"); err != nil {
+		t.Errorf("learned ISIN should survive a reload: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := reg.SecurityBySymbol(ctx, "NABIL"); err != nil {
+		t.Fatalf("SecurityBySymbol failed: %v", err)
+	}
+	if securityCalls.Load() != 3 {
+		t.Errorf("expected reload after TTL, got %d fetches", securityCalls.Load())
+	}
+
+	reg.Invalidate()
+	if !reg.LoadedAt().IsZero() {
+		t.Error("expected zero LoadedAt after Invalidate")
+	}
+	if _, err := reg.SecurityBySymbol(ctx, "NABIL"); err != nil {
+		t.Fatalf("SecurityBySymbol failed: %v", err)
+	}
+	if securityCalls.Load() != 4 {
+		t.Errorf("expected reload after Invalidate, got %d fetches", securityCalls.Load())
+	}
+}
+
+func TestRegistry_CompanyListFailureIsNotFatal(t *testing.T) {
+	server := httptest.NewServer(serveToken(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/nots/security":
+			json.NewEncoder(w).Encode([]Security{{ID: 131, Symbol: "NABIL", ActiveStatus: "A"}})
+		default:
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, Options{})
+	reg := client.Registry()
+	ctx := context.Background()
+
+	sec, err := reg.SecurityBySymbol(ctx, "NABIL")
+	if err != nil {
+		t.Fatalf("SecurityBySymbol failed: %v", err)
+	}
+	if sec.ID != 131 {
+		t.Errorf("expected ID 131, got %d", sec.ID)
+	}
+	if _, err := reg.CompanyBySymbol(ctx, "NABIL"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound without company listings, got %v", err)
+	}
+}
+
+func TestRegistry_CanceledCallerDoesNotFailOthers(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(serveToken(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/nots/security":
+			<-release
+			json.NewEncoder(w).Encode([]Security{{ID: 131, Symbol: "NABIL", ActiveStatus: "A"}})
+		case "/api/nots/company/list":
+			json.NewEncoder(w).Encode([]Company{})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, Options{})
+	reg := client.Registry()
+
+	cctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := reg.SecurityBySymbol(cctx, "NABIL")
+		first <- err
+	}()
+	second := make(chan error, 1)
+	go func() {
+		_, err := reg.SecurityBySymbol(context.Background(), "NABIL")
+		second <- err
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for the canceled caller, got %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the shared load to finish for the other caller, got %v", err)
+	}
+}
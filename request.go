@@ -0,0 +1,149 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PayloadKind selects the payload ID [Client.Do] sends as a POST body.
+// NEPSE rejects graph and security-detail POSTs without the right one.
+type PayloadKind int
+
+const (
+	PayloadNone  PayloadKind = iota // Send Request.Body as given
+	PayloadScrip                    // {"id": ...} for security graph and security-detail endpoints
+	PayloadIndex                    // {"id": ...} for index graph endpoints; depends on the token's salts
+)
+
+// Request describes an authenticated call to any NEPSE endpoint, including
+// ones the client has no method for.
+type Request struct {
+	Method  string      // http.MethodGet or http.MethodPost; empty means GET
+	Path    string      // Path under BaseURL, e.g. "/api/nots/market/graphdata/daily/58"
+	Query   url.Values  // Appended to Path
+	Body    any         // POST body, encoded as JSON; must be nil when Payload is set
+	Payload PayloadKind // POST the computed payload ID instead of Body
+}
+
+// endpoint returns the path with the query appended.
+func (r Request) endpoint() string {
+	if len(r.Query) == 0 {
+		return r.Path
+	}
+	sep := "?"
+	if strings.Contains(r.Path, "?") {
+		sep = "&"
+	}
+	return r.Path + sep + r.Query.Encode()
+}
+
+// Do sends an authenticated request and decodes the JSON response into out,
+// which may be nil to discard it or a *[]byte to receive the raw body.
+// Requests go through the same token handling, rate limiting, retries,
+// response cache, and error mapping as the built-in methods.
+func (c *Client) Do(ctx context.Context, r Request, out any) error {
+	ctx = withOperation(ctx, "Do")
+
+	if !strings.HasPrefix(r.Path, "/") {
+		return NewInvalidClientRequestError("request path must start with /")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	switch r.Method {
+	case "", http.MethodGet:
+		if r.Body != nil || r.Payload != PayloadNone {
+			return NewInvalidClientRequestError("GET request cannot have a body")
+		}
+		data, err = c.apiRequestRaw(ctx, r.endpoint())
+	case http.MethodPost:
+		body, berr := c.requestBody(r)
+		if berr != nil {
+			return berr
+		}
+		data, err = c.apiPostRequestRaw(ctx, r.endpoint(), body)
+		if r.Payload != PayloadNone {
+			err = c.payloadRejected(err)
+		}
+	default:
+		return NewInvalidClientRequestError("unsupported request method " + r.Method)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*out = data
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return NewInternalError("failed to decode response", err)
+	}
+	return nil
+}
+
+// requestBody returns the POST body for r, computing the payload ID on each
+// attempt when one is requested.
+func (c *Client) requestBody(r Request) (any, error) {
+	if r.Payload == PayloadNone {
+		return r.Body, nil
+	}
+	if r.Body != nil {
+		return nil, NewInvalidClientRequestError("request cannot set both Body and Payload")
+	}
+
+	var compute func(context.Context) (int, error)
+	switch r.Payload {
+	case PayloadScrip:
+		compute = c.computeScripGraphPayloadID
+	case PayloadIndex:
+		compute = c.computeIndexGraphPayloadID
+	default:
+		return nil, NewInvalidClientRequestError("unknown payload kind")
+	}
+	return bodyFunc(func(ctx context.Context) (any, error) {
+		id, err := compute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return graphPostPayload{ID: id}, nil
+	}), nil
+}
+
+// Get fetches path with the given query and decodes the JSON response as T.
+//
+// Example:
+//
+//	type notice struct{ ID int; NoticeHeading string }
+//	notices, err := nepse.Get[[]notice](ctx, client, "/api/nots/news/companies/disclosure", nil)
+func Get[T any](ctx context.Context, c *Client, path string, query url.Values) (T, error) {
+	ctx = withOperation(ctx, "Get")
+	var out T
+	err := c.Do(ctx, Request{Path: path, Query: query}, &out)
+	return out, err
+}
+
+// Post sends body as JSON to path and decodes the JSON response as T.
+func Post[T any](ctx context.Context, c *Client, path string, body any) (T, error) {
+	ctx = withOperation(ctx, "Post")
+	var out T
+	err := c.Do(ctx, Request{Method: http.MethodPost, Path: path, Body: body}, &out)
+	return out, err
+}
+
+// PostPayload POSTs the payload ID of the given kind to path and decodes
+// the JSON response as T, for graph-style endpoints the client doesn't wrap.
+func PostPayload[T any](ctx context.Context, c *Client, path string, kind PayloadKind) (T, error) {
+	ctx = withOperation(ctx, "PostPayload")
+	var out T
+	err := c.Do(ctx, Request{Method: http.MethodPost, Path: path, Payload: kind}, &out)
+	return out, err
+}
@@ -0,0 +1,168 @@
+package nepse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+// notice is a response type the client has no method for.
+type notice struct {
+	ID            int    `json:"id"`
+	NoticeHeading string `json:"noticeHeading"`
+}
+
+// newRequestTestClient returns a client whose authenticated requests go to
+// handler, failing the test for any request sent without a token.
+func newRequestTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := newTestServer(serveToken(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			t.Errorf("%s %s sent without Authorization", r.Method, r.URL.Path)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return newTestClient(t, server, Options{})
+}
+
+func TestGet(t *testing.T) {
+	client := newRequestTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/nots/news/companies/disclosure" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("page"); got != "2" {
+			t.Errorf("page = %q, want 2", got)
+		}
+		if got := r.URL.Query().Get("size"); got != "10" {
+			t.Errorf("size = %q, want 10", got)
+		}
+		w.Write([]byte(`[{"id":7,"noticeHeading":"AGM"}]`))
+	})
+
+	ctx := context.Background()
+	notices, err := Get[[]notice](ctx, client, "/api/nots/news/companies/disclosure", url.Values{"page": {"2"}, "size": {"10"}})
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(notices) != 1 || notices[0].NoticeHeading != "AGM" {
+		t.Errorf("notices = %+v", notices)
+	}
+
+	_, err = Get[[]notice](ctx, client, "/api/nots/unknown", nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown path error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPost(t *testing.T) {
+	client := newRequestTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		io.Copy(w, r.Body)
+	})
+
+	got, err := Post[notice](context.Background(), client, "/api/nots/echo", notice{ID: 3, NoticeHeading: "echo"})
+	if err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	if got.ID != 3 || got.NoticeHeading != "echo" {
+		t.Errorf("Post = %+v", got)
+	}
+}
+
+func TestPostPayload(t *testing.T) {
+	srv, client := newPayloadServer(t)
+	ctx := context.Background()
+
+	if _, err := PostPayload[[]GraphDataPoint](ctx, client, "/api/nots/market/graphdata/daily/58", PayloadIndex); err != nil {
+		t.Fatalf("PostPayload index failed: %v", err)
+	}
+	if got, want := srv.lastID(), payload.IndexGraph(61, 1, proveSalts(1)); got != want {
+		t.Errorf("index payload id = %d, want %d", got, want)
+	}
+
+	if _, err := PostPayload[[]GraphDataPoint](ctx, client, "/api/nots/market/graphdata/daily/131", PayloadScrip); err != nil {
+		t.Fatalf("PostPayload scrip failed: %v", err)
+	}
+	if got, want := srv.lastID(), payload.ScripGraph(61, 1); got != want {
+		t.Errorf("scrip payload id = %d, want %d", got, want)
+	}
+
+	// A rejected payload invalidates the cached IDs like the built-in methods.
+	srv.rejectPosts.Store(1)
+	if _, err := PostPayload[[]GraphDataPoint](ctx, client, "/api/nots/market/graphdata/daily/131", PayloadScrip); err == nil {
+		t.Fatal("expected rejected POST to fail")
+	}
+	if _, err := PostPayload[[]GraphDataPoint](ctx, client, "/api/nots/market/graphdata/daily/131", PayloadScrip); err != nil {
+		t.Fatalf("PostPayload after rejection failed: %v", err)
+	}
+	if n := srv.statusHits.Load(); n != 2 {
+		t.Errorf("market status fetched %d times, want 2", n)
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	client := newRequestTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"raw":true}`))
+	})
+	ctx := context.Background()
+
+	var raw []byte
+	if err := client.Do(ctx, Request{Path: "/api/nots/anything", Query: url.Values{"a": {"1"}}}, &raw); err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if string(raw) != `{"raw":true}` {
+		t.Errorf("raw = %s", raw)
+	}
+	if err := client.Do(ctx, Request{Method: http.MethodPost, Path: "/api/nots/anything"}, nil); err != nil {
+		t.Fatalf("Do with nil out failed: %v", err)
+	}
+
+	invalid := []struct {
+		name string
+		req  Request
+	}{
+		{"relative path", Request{Path: "api/nots/x"}},
+		{"unsupported method", Request{Method: http.MethodDelete, Path: "/api/nots/x"}},
+		{"GET with body", Request{Path: "/api/nots/x", Body: 1}},
+		{"GET with payload", Request{Path: "/api/nots/x", Payload: PayloadScrip}},
+		{"body and payload", Request{Method: http.MethodPost, Path: "/api/nots/x", Body: 1, Payload: PayloadScrip}},
+		{"unknown payload", Request{Method: http.MethodPost, Path: "/api/nots/x", Payload: PayloadKind(9)}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.Do(ctx, tt.req, nil)
+			if !errors.Is(err, ErrInvalidClientRequest) {
+				t.Errorf("err = %v, want ErrInvalidClientRequest", err)
+			}
+		})
+	}
+}
+
+func TestRequest_Endpoint(t *testing.T) {
+	tests := []struct {
+		req  Request
+		want string
+	}{
+		{Request{Path: "/api/nots/x"}, "/api/nots/x"},
+		{Request{Path: "/api/nots/x", Query: url.Values{"b": {"2"}, "a": {"1"}}}, "/api/nots/x?a=1&b=2"},
+		{Request{Path: "/api/nots/x?size=500", Query: url.Values{"page": {"0"}}}, "/api/nots/x?size=500&page=0"},
+	}
+	for _, tt := range tests {
+		if got := tt.req.endpoint(); got != tt.want {
+			t.Errorf("endpoint() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package nepse
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRetryDelay caps the computed backoff of [ExponentialBackoff].
+const DefaultMaxRetryDelay = 30 * time.Second
+
+// RetryPolicy decides whether a failed request is retried and how long to wait first.
+//
+// Retry is called after every attempt that returned a transport error or an
+// HTTP status of 400 or above. attempt counts the attempts made so far,
+// starting at 1. Exactly one of resp and err is non-nil; only resp's status
+// and headers may be inspected, as the body is owned by the client.
+type RetryPolicy interface {
+	Retry(attempt int, resp *http.Response, err error) (delay time.Duration, retry bool)
+}
+
+// RetryPolicyFunc adapts a function to the [RetryPolicy] interface.
+type RetryPolicyFunc func(attempt int, resp *http.Response, err error) (time.Duration, bool)
+
+// Retry calls f(attempt, resp, err).
+func (f RetryPolicyFunc) Retry(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	return f(attempt, resp, err)
+}
+
+// ExponentialBackoff is the default [RetryPolicy]. It retries network errors,
+// 5xx responses, and 429s using exponential backoff with full jitter, and
+// waits exactly as long as the server asks when a Retry-After header is present.
+type ExponentialBackoff struct {
+	MaxRetries int           // Retries after the first attempt
+	BaseDelay  time.Duration // Upper bound of the first delay; doubles each attempt
+	MaxDelay   time.Duration // Upper bound of any computed delay; zero uses DefaultMaxRetryDelay
+}
+
+// NewExponentialBackoff returns the default policy for the given retry budget.
+func NewExponentialBackoff(maxRetries int, baseDelay time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		MaxRetries: maxRetries,
+		BaseDelay:  baseDelay,
+		MaxDelay:   DefaultMaxRetryDelay,
+	}
+}
+
+// Retry implements [RetryPolicy].
+func (b *ExponentialBackoff) Retry(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt > b.MaxRetries {
+		return 0, false
+	}
+	if err == nil && !isRetryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d, true
+		}
+	}
+
+	maxDelay := b.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxRetryDelay
+	}
+	ceiling := maxDelay
+	if shift := attempt - 1; shift < 63 && b.BaseDelay <= maxDelay>>shift {
+		ceiling = b.BaseDelay << shift
+	}
+	if ceiling <= 0 {
+		return 0, true
+	}
+	// Full jitter: spread retries uniformly over [0, ceiling].
+	return rand.N(ceiling + 1), true
+}
+
+func isRetryableStatus(code int) bool {
+	return code >= 500 || code == http.StatusTooManyRequests
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds
+// or as an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// fitsDeadline reports whether a retry after delay would start before ctx's deadline.
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Now().Add(delay).Before(deadline)
+}
+
+// attemptRequest returns the request to send for the given attempt. Requests
+// with a body get a fresh copy of it from GetBody so POSTs replay correctly.
+func attemptRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+// discardBody drains and closes a response body so its connection can be reused.
+func discardBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,191 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoff_Retry(t *testing.T) {
+	policy := NewExponentialBackoff(3, 100*time.Millisecond)
+	status := func(code int, header ...string) *http.Response {
+		resp := &http.Response{StatusCode: code, Header: http.Header{}}
+		if len(header) == 2 {
+			resp.Header.Set(header[0], header[1])
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name      string
+		attempt   int
+		resp      *http.Response
+		err       error
+		wantRetry bool
+		maxDelay  time.Duration
+	}{
+		{"network error", 1, nil, errors.New("connection reset"), true, 100 * time.Millisecond},
+		{"server error", 2, status(http.StatusBadGateway), nil, true, 200 * time.Millisecond},
+		{"rate limited", 3, status(http.StatusTooManyRequests), nil, true, 400 * time.Millisecond},
+		{"budget exhausted", 4, status(http.StatusServiceUnavailable), nil, false, 0},
+		{"client error", 1, status(http.StatusNotFound), nil, false, 0},
+		{"unauthorized", 1, status(http.StatusUnauthorized), nil, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				delay, retry := policy.Retry(tt.attempt, tt.resp, tt.err)
+				if retry != tt.wantRetry {
+					t.Fatalf("retry = %v, want %v", retry, tt.wantRetry)
+				}
+				if delay < 0 || delay > tt.maxDelay {
+					t.Fatalf("delay %v outside [0, %v]", delay, tt.maxDelay)
+				}
+			}
+		})
+	}
+
+	t.Run("Retry-After overrides backoff", func(t *testing.T) {
+		delay, retry := policy.Retry(1, status(http.StatusTooManyRequests, "Retry-After", "2"), nil)
+		if !retry || delay != 2*time.Second {
+			t.Errorf("got (%v, %v), want (2s, true)", delay, retry)
+		}
+	})
+
+	t.Run("delay capped at MaxDelay", func(t *testing.T) {
+		p := &ExponentialBackoff{MaxRetries: 100, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+		for i := 0; i < 50; i++ {
+			if delay, _ := p.Retry(60, nil, errors.New("boom")); delay > 5*time.Second {
+				t.Fatalf("delay %v exceeds MaxDelay", delay)
+			}
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-1", 0, false},
+		{"Fri, 02 Jan 2026 10:00:30 GMT", 30 * time.Second, true},
+		{"Fri, 02 Jan 2026 09:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = (%v, %v), want (%v, %v)", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestClient_RetryReplaysPostBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tokenResponse())
+		case "/api/test/post":
+			b, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			bodies = append(bodies, string(b))
+			n := len(bodies)
+			mu.Unlock()
+			if n == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"ok":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewClient(&Options{
+		BaseURL:     server.URL,
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  2,
+		RetryDelay:  time.Millisecond,
+		Config: &Config{
+			BaseURL: server.URL,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	body, err := client.DebugRawPostRequest(context.Background(), "/api/test/post", graphPostPayload{ID: 42})
+	if err != nil {
+		t.Fatalf("DebugRawPostRequest failed: %v", err)
+	}
+	if string(body) != `{"ok":true}` {
+		t.Errorf("unexpected body %q", body)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 POST attempts, got %d", len(bodies))
+	}
+	for i, b := range bodies {
+		if b != `{"id":42}` {
+			t.Errorf("attempt %d sent body %q, want %q", i+1, b, `{"id":42}`)
+		}
+	}
+}
+
+func TestClient_RetrySkippedPastDeadline(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client, err := NewClient(&Options{
+		BaseURL:     server.URL,
+		HTTPTimeout: 5 * time.Second,
+		MaxRetries:  3,
+		RetryDelay:  time.Millisecond,
+		Config: &Config{
+			BaseURL: server.URL,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.Token(ctx)
+	if !errors.Is(err, ErrInvalidServerResponse) {
+		t.Errorf("expected ErrInvalidServerResponse, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("expected immediate failure instead of waiting for Retry-After, took %v", elapsed)
+	}
+}
@@ -0,0 +1,209 @@
+package nepse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/voidarchive/go-nepse/internal/auth"
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+// AuthStage is a step of [Client.AuthSelfTest], in the order they run.
+type AuthStage string
+
+const (
+	AuthStageToken   AuthStage = "token"   // Fetch /api/authenticate/prove
+	AuthStageDecode  AuthStage = "decode"  // Compute junk-character indices and strip the tokens
+	AuthStageGET     AuthStage = "get"     // Authenticated GET of the market status
+	AuthStagePayload AuthStage = "payload" // Compute graph payload IDs from the market status and salts
+	AuthStagePOST    AuthStage = "post"    // Authenticated POST of the NEPSE index graph with its payload ID
+)
+
+// AuthProbe is the outcome of one probe request.
+type AuthProbe struct {
+	Method   string
+	Endpoint string
+	Status   int // Zero if no response was received
+	Latency  time.Duration
+}
+
+// AuthReport is the result of [Client.AuthSelfTest]. Fields are filled in as
+// stages pass, so everything before FailedStage is populated.
+type AuthReport struct {
+	FailedStage AuthStage // Empty when every stage passed
+	Err         error     `json:"-"` // Why FailedStage failed
+	Error       string    // Err's message, so encoded reports keep it
+
+	// Token stage.
+	Salts        [5]int
+	RawAccess    string        `json:"-"` // Access token as served, junk included
+	ServerTime   time.Time     // NEPSE's serverTime from the token response
+	ClockOffset  time.Duration // ServerTime minus the local clock at the midpoint of the request
+	TokenLatency time.Duration
+
+	// Decode stage. The stripped tokens are live credentials, so they and
+	// RawAccess are left out of JSON.
+	AccessIndices  []int
+	RefreshIndices []int
+	AccessToken    string `json:"-"`
+	RefreshToken   string `json:"-"`
+
+	// GET and payload stages.
+	GET            AuthProbe
+	MarketStatusID int32
+	Day            int // Day of month in Nepal by NEPSE's clock
+	ScripPayloadID int // Base payload ID, as sent to security graph and detail endpoints
+	IndexPayloadID int // Salted payload ID, as sent to index graph endpoints
+	POST           AuthProbe
+}
+
+// OK reports whether every stage passed.
+func (r *AuthReport) OK() bool { return r.FailedStage == "" }
+
+// String formats the report for humans, one line per fact. Tokens are
+// abbreviated.
+func (r *AuthReport) String() string {
+	var b strings.Builder
+	if r.OK() {
+		b.WriteString("auth self-test: OK\n")
+	} else {
+		fmt.Fprintf(&b, "auth self-test: FAILED at %s: %v\n", r.FailedStage, r.Err)
+	}
+	fmt.Fprintf(&b, "salts:            %v\n", r.Salts)
+	fmt.Fprintf(&b, "server time:      %s (offset %v, latency %v)\n", r.ServerTime.Format(time.RFC3339Nano), r.ClockOffset, r.TokenLatency)
+	fmt.Fprintf(&b, "access indices:   %v\n", r.AccessIndices)
+	fmt.Fprintf(&b, "refresh indices:  %v\n", r.RefreshIndices)
+	fmt.Fprintf(&b, "raw access:       %s\n", abbreviate(r.RawAccess))
+	fmt.Fprintf(&b, "access token:     %s\n", abbreviate(r.AccessToken))
+	fmt.Fprintf(&b, "GET probe:        %s\n", r.GET)
+	fmt.Fprintf(&b, "market status ID: %d (day %d)\n", r.MarketStatusID, r.Day)
+	fmt.Fprintf(&b, "payload IDs:      scrip %d, index %d\n", r.ScripPayloadID, r.IndexPayloadID)
+	fmt.Fprintf(&b, "POST probe:       %s\n", r.POST)
+	return b.String()
+}
+
+func (p AuthProbe) String() string {
+	if p.Method == "" {
+		return "not run"
+	}
+	return fmt.Sprintf("%s %s -> %d in %v", p.Method, p.Endpoint, p.Status, p.Latency)
+}
+
+// abbreviate shortens a token to its ends and length.
+func abbreviate(s string) string {
+	if len(s) <= 16 {
+		return s
+	}
+	return fmt.Sprintf("%s...%s (%d chars)", s[:8], s[len(s)-8:], len(s))
+}
+
+// AuthSelfTest diagnoses authentication end to end. It fetches a fresh token,
+// decodes it, then uses that token for one GET (market status) and one POST
+// (NEPSE index graph with its salted payload ID), stopping at the first stage
+// that fails. The client's own token is not touched.
+//
+// The report is always returned; the error is non-nil exactly when a stage
+// failed and equals report.Err.
+func (c *Client) AuthSelfTest(ctx context.Context) (*AuthReport, error) {
+	ctx = withOperation(ctx, "AuthSelfTest")
+	r := &AuthReport{}
+	fail := func(stage AuthStage, err error) (*AuthReport, error) {
+		r.FailedStage, r.Err, r.Error = stage, err, err.Error()
+		return r, err
+	}
+
+	sent := c.now()
+	tr, err := c.Token(ctx)
+	received := c.now()
+	if err != nil {
+		return fail(AuthStageToken, err)
+	}
+	r.Salts = [5]int{tr.Salt1, tr.Salt2, tr.Salt3, tr.Salt4, tr.Salt5}
+	r.RawAccess = tr.AccessToken
+	r.TokenLatency = received.Sub(sent)
+	if tr.ServerTime <= 0 {
+		return fail(AuthStageToken, NewInvalidServerResponseError("token response has no serverTime"))
+	}
+	r.ServerTime = time.UnixMilli(tr.ServerTime)
+	r.ClockOffset = r.ServerTime.Sub(sent.Add(r.TokenLatency / 2))
+
+	decoded, err := c.authManager.Decode(*tr)
+	if err != nil {
+		return fail(AuthStageDecode, err)
+	}
+	r.AccessIndices, r.RefreshIndices = decoded.AccessIndices, decoded.RefreshIndices
+	r.AccessToken, r.RefreshToken = decoded.AccessToken, decoded.RefreshToken
+	if r.AccessToken == "" {
+		return fail(AuthStageDecode, NewInvalidServerResponseError("decoded access token is empty"))
+	}
+
+	var status MarketStatus
+	r.GET, err = c.probe(ctx, http.MethodGet, c.config.Endpoints.MarketOpen, nil, r.AccessToken, &status)
+	if err != nil {
+		return fail(AuthStageGET, err)
+	}
+
+	r.MarketStatusID = status.ID
+	r.Day = payload.Day(r.ServerTime)
+	if status.ID == 0 {
+		return fail(AuthStagePayload, NewInvalidServerResponseError("market status has no ID to derive payload IDs from"))
+	}
+	// Same arithmetic as computeScripGraphPayloadID and
+	// computeIndexGraphPayloadID, but with this token's salts and clock.
+	r.ScripPayloadID = c.payload.Base(int(status.ID), r.Day)
+	salts := auth.Salts{Salt1: tr.Salt1, Salt2: tr.Salt2, Salt3: tr.Salt3, Salt4: tr.Salt4, Salt5: tr.Salt5}
+	r.IndexPayloadID = payload.Index(r.ScripPayloadID, r.Day, salts)
+
+	var graph []GraphDataPoint
+	r.POST, err = c.probe(ctx, http.MethodPost, c.indexEndpoint(IndexNepse), graphPostPayload{ID: r.IndexPayloadID}, r.AccessToken, &graph)
+	if err != nil {
+		return fail(AuthStagePOST, err)
+	}
+	return r, nil
+}
+
+// probe sends one request authorised with token, bypassing the client's
+// token manager, and decodes a 200 response into result.
+func (c *Client) probe(ctx context.Context, method, endpoint string, body any, token string, result any) (AuthProbe, error) {
+	p := AuthProbe{Method: method, Endpoint: endpoint}
+
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return p, NewInternalError("failed to marshal request body", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return p, NewInternalError("failed to create request", err)
+	}
+	auth.SetAuthHeader(req, token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.setCommonHeaders(req)
+
+	start := time.Now()
+	resp, stats, err := c.doRequest(req)
+	p.Latency = time.Since(start)
+	if err != nil {
+		return p, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	p.Status = resp.StatusCode
+
+	if resp.StatusCode != http.StatusOK {
+		return p, statusError(req, resp, stats)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return p, NewInternalError("failed to decode response", err)
+	}
+	return p, nil
+}
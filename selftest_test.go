@@ -0,0 +1,137 @@
+package nepse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/voidarchive/go-nepse/internal/auth"
+	"github.com/voidarchive/go-nepse/payload"
+)
+
+// selfTestServer serves the endpoints AuthSelfTest probes. fail maps a path
+// to the status it should return instead.
+func selfTestServer(t *testing.T, fail map[string]int) *httptest.Server {
+	t.Helper()
+	token := tokenResponse()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status, ok := fail[r.URL.Path]; ok {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			json.NewEncoder(w).Encode(token)
+		case "/api/nots/nepse-data/market-open":
+			json.NewEncoder(w).Encode(MarketStatus{IsOpen: "OPEN", ID: 61})
+		case "/api/nots/graph/index/58":
+			var body graphPostPayload
+			json.NewDecoder(r.Body).Decode(&body)
+			day := payload.Day(time.UnixMilli(token.ServerTime))
+			salts := auth.Salts{Salt1: token.Salt1, Salt2: token.Salt2, Salt3: token.Salt3, Salt4: token.Salt4, Salt5: token.Salt5}
+			if body.ID != payload.Index(payload.Base(61, day), day, salts) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`[[1735700000,2600.5]]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestClient_AuthSelfTest(t *testing.T) {
+	server := selfTestServer(t, nil)
+	defer server.Close()
+	client := newTestClient(t, server, Options{})
+
+	report, err := client.AuthSelfTest(context.Background())
+	if err != nil {
+		t.Fatalf("AuthSelfTest failed at %s: %v", report.FailedStage, err)
+	}
+	if !report.OK() {
+		t.Errorf("OK() = false for %+v", report)
+	}
+
+	want := tokenResponse()
+	if report.Salts != [5]int{want.Salt1, want.Salt2, want.Salt3, want.Salt4, want.Salt5} {
+		t.Errorf("Salts = %v", report.Salts)
+	}
+	if len(report.AccessIndices) != 5 || len(report.RefreshIndices) != 5 {
+		t.Errorf("indices = %v / %v", report.AccessIndices, report.RefreshIndices)
+	}
+	if report.AccessToken == "" || report.RawAccess != want.AccessToken {
+		t.Errorf("AccessToken %q, RawAccess %q", report.AccessToken, report.RawAccess)
+	}
+	if report.ClockOffset.Abs() > time.Second {
+		t.Errorf("ClockOffset = %v against a local server", report.ClockOffset)
+	}
+	if report.MarketStatusID != 61 || report.ScripPayloadID != payload.Base(61, report.Day) {
+		t.Errorf("payload stage: status %d, scrip payload %d", report.MarketStatusID, report.ScripPayloadID)
+	}
+	if report.GET.Status != http.StatusOK || report.POST.Status != http.StatusOK {
+		t.Errorf("probes: GET %v, POST %v", report.GET, report.POST)
+	}
+	if s := report.String(); !strings.Contains(s, "OK") || strings.Contains(s, report.AccessToken) {
+		t.Errorf("String() should report OK and abbreviate tokens:\n%s", s)
+	}
+	b, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for _, tok := range []string{report.RawAccess, report.AccessToken, report.RefreshToken} {
+		if strings.Contains(string(b), tok) {
+			t.Errorf("JSON report contains a token:\n%s", b)
+		}
+	}
+}
+
+func TestClient_AuthSelfTestFailedStage(t *testing.T) {
+	tests := []struct {
+		name   string
+		fail   map[string]int
+		stage  AuthStage
+		target error
+	}{
+		{"token", map[string]int{"/api/authenticate/prove": http.StatusForbidden}, AuthStageToken, ErrUnauthorized},
+		{"get", map[string]int{"/api/nots/nepse-data/market-open": http.StatusUnauthorized}, AuthStageGET, ErrTokenExpired},
+		{"post", map[string]int{"/api/nots/graph/index/58": http.StatusBadRequest}, AuthStagePOST, ErrInvalidClientRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := selfTestServer(t, tt.fail)
+			defer server.Close()
+			client := newTestClient(t, server, Options{})
+
+			report, err := client.AuthSelfTest(context.Background())
+			if report.FailedStage != tt.stage {
+				t.Errorf("FailedStage = %q, want %q", report.FailedStage, tt.stage)
+			}
+			if !errors.Is(err, tt.target) || err != report.Err {
+				t.Errorf("err = %v, report.Err = %v, want %v", err, report.Err, tt.target)
+			}
+			if !strings.Contains(report.String(), "FAILED at "+string(tt.stage)) {
+				t.Errorf("String() does not name the failed stage:\n%s", report)
+			}
+
+			b, err := json.Marshal(report)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			var decoded struct{ FailedStage, Error string }
+			if err := json.Unmarshal(b, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if decoded.FailedStage != string(tt.stage) || decoded.Error == "" || decoded.Error != report.Err.Error() {
+				t.Errorf("JSON report does not say why %s failed:\n%s", tt.stage, b)
+			}
+		})
+	}
+}
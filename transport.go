@@ -5,7 +5,9 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -36,12 +38,46 @@ func initClient(options *Options) (*Client, error) {
 		httpClient: hc,
 		config:     options.Config,
 		options:    options,
+		logger:     options.Logger,
 	}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
+	}
+	c.doer = chainMiddleware(hc, options.Middleware)
+	c.registry = newSecurityRegistry(c, options.RegistryTTL)
+	c.limiter = newRateLimiter(options.RateLimit, options.RateBurst)
+	c.retryPolicy = options.RetryPolicy
+	if c.retryPolicy == nil {
+		c.retryPolicy = NewExponentialBackoff(options.MaxRetries, options.RetryDelay)
+	}
+
+	artifacts, err := loadAuthArtifacts(options)
+	if err != nil {
+		return nil, err
+	}
+	c.payload = artifacts.table
 
-	authManager, err := auth.NewManager(c)
+	authOpts := []auth.Option{auth.WithLogger(c.logger), auth.WithClock(options.Clock)}
+	if options.TokenParser == TokenParserNative {
+		authOpts = append(authOpts, auth.WithNativeParser())
+	}
+	if artifacts.module != nil {
+		authOpts = append(authOpts, auth.WithWASMModule(artifacts.module))
+	}
+	if options.OnTokenRefresh != nil {
+		authOpts = append(authOpts, auth.WithRefreshHook(options.OnTokenRefresh))
+	}
+	authManager, err := auth.NewManager(c, authOpts...)
 	if err != nil {
 		return nil, NewInternalError("failed to create auth manager", err)
 	}
+	if err := artifacts.verify(authManager); err != nil {
+		_ = authManager.Close()
+		return nil, err
+	}
+	if options.BackgroundRefresh {
+		authManager.StartBackgroundRefresh()
+	}
 	c.authManager = authManager
 
 	return c, nil
@@ -49,6 +85,8 @@ func initClient(options *Options) (*Client, error) {
 
 // Token implements auth.NepseHTTP interface.
 func (c *Client) Token(ctx context.Context) (*auth.TokenResponse, error) {
+	ctx = withOperation(ctx, "Token")
+
 	url := c.config.BaseURL + "/api/authenticate/prove"
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -58,14 +96,14 @@ func (c *Client) Token(ctx context.Context) (*auth.TokenResponse, error) {
 
 	c.setCommonHeaders(req)
 
-	resp, err := c.doRequest(req)
+	resp, stats, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, MapHTTPStatusToError(resp.StatusCode, resp.Status)
+		return nil, statusError(req, resp, stats)
 	}
 
 	var tokenResp auth.TokenResponse
@@ -76,40 +114,152 @@ func (c *Client) Token(ctx context.Context) (*auth.TokenResponse, error) {
 	return &tokenResp, nil
 }
 
-func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
-	var lastErr error
-	maxDelay := 30 * time.Second
+// RenewToken implements auth.TokenRenewer. It exchanges a decoded refresh
+// token for a new token response, which carries fresh salts like Token's.
+func (c *Client) RenewToken(ctx context.Context, refreshToken string) (*auth.TokenResponse, error) {
+	ctx = withOperation(ctx, "RenewToken")
 
-	for attempt := 0; attempt <= c.options.MaxRetries; attempt++ {
-		if attempt > 0 {
-			delay := min(c.options.RetryDelay*time.Duration(1<<uint(attempt-1)), maxDelay)
+	url := c.config.BaseURL + "/api/authenticate/refresh-token"
+	body, err := json.Marshal(map[string]string{"refreshToken": refreshToken})
+	if err != nil {
+		return nil, NewInternalError("failed to encode refresh request", err)
+	}
 
-			timer := time.NewTimer(delay)
-			select {
-			case <-req.Context().Done():
-				timer.Stop()
-				return nil, req.Context().Err()
-			case <-timer.C:
-			}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, NewInternalError("failed to create request", err)
+	}
+
+	c.setCommonHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, stats, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %w", auth.ErrRefreshRejected, statusError(req, resp, stats))
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("%w: %w", auth.ErrRefreshUnsupported, statusError(req, resp, stats))
+	default:
+		return nil, statusError(req, resp, stats)
+	}
+
+	var tokenResp auth.TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, NewInternalError("failed to decode token response", err)
+	}
+
+	return &tokenResp, nil
+}
+
+// requestStats describes how a request fared across retries.
+type requestStats struct {
+	attempts int
+	elapsed  time.Duration
+}
+
+// doRequest sends req, retrying as the client's RetryPolicy directs.
+// The final response is returned as-is, even for error statuses, so callers
+// can map it with [statusError]; only transport failures are returned as errors.
+func (c *Client) doRequest(req *http.Request) (*http.Response, requestStats, error) {
+	ctx := req.Context()
+	first := time.Now()
+	stats := func(attempt int) requestStats {
+		return requestStats{attempts: attempt, elapsed: time.Since(first)}
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, stats(attempt - 1), err
 		}
 
-		resp, err := c.httpClient.Do(req)
+		attemptReq, err := attemptRequest(req, attempt)
 		if err != nil {
-			lastErr = NewNetworkError(err)
-			continue
+			return nil, stats(attempt - 1), NewInternalError("failed to rewind request body", err)
 		}
 
-		// Retry on server errors and rate limits
-		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
-			_ = resp.Body.Close()
-			lastErr = MapHTTPStatusToError(resp.StatusCode, resp.Status)
-			continue
+		start := time.Now()
+		resp, err := c.doer.Do(attemptReq)
+		c.logAttempt(req, attempt, resp, err, time.Since(start))
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return resp, stats(attempt), nil
+		}
+
+		delay, retry := c.retryPolicy.Retry(attempt, resp, err)
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			// Hold back every goroutine sharing this client, not just this one.
+			c.limiter.pause(delay)
+		}
+		// Don't start a retry that the caller's deadline would cut short.
+		if retry && !fitsDeadline(ctx, delay) {
+			retry = false
 		}
 
-		return resp, nil
+		if !retry {
+			if err != nil {
+				return nil, stats(attempt), NewNetworkError(err).withRequest(req, nil, stats(attempt))
+			}
+			return resp, stats(attempt), nil
+		}
+
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "nepse request retrying",
+			slog.String("method", req.Method),
+			slog.String("endpoint", c.endpointLabel(req)),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+		)
+		if resp != nil {
+			discardBody(resp)
+		}
+		if err := sleepCtx(ctx, delay); err != nil {
+			return nil, stats(attempt), err
+		}
+	}
+}
+
+// statusError builds the error for a final non-OK response and closes its body.
+func statusError(req *http.Request, resp *http.Response, stats requestStats) *NepseError {
+	defer func() { _ = resp.Body.Close() }()
+	return MapHTTPStatusToError(resp.StatusCode, resp.Status).withRequest(req, resp, stats)
+}
+
+// logAttempt records the outcome of a single HTTP attempt.
+func (c *Client) logAttempt(req *http.Request, attempt int, resp *http.Response, err error, latency time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("operation", Operation(req.Context())),
+		slog.String("method", req.Method),
+		slog.String("endpoint", c.endpointLabel(req)),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", attempt),
+		slog.Duration("latency", latency),
 	}
+	if err != nil {
+		c.logger.LogAttrs(req.Context(), slog.LevelWarn, "nepse request failed", append(attrs, slog.Any("error", err))...)
+		return
+	}
+	level := slog.LevelDebug
+	if resp.StatusCode >= http.StatusBadRequest {
+		level = slog.LevelWarn
+	}
+	c.logger.LogAttrs(req.Context(), level, "nepse request", append(attrs, slog.Int("status", resp.StatusCode))...)
+}
 
-	return nil, lastErr
+// endpointLabel names the endpoint a request targets, e.g. "MarketDepth",
+// falling back to the URL path for endpoints outside Endpoints.
+func (c *Client) endpointLabel(req *http.Request) string {
+	endpoint := req.URL.Path
+	if req.URL.RawQuery != "" {
+		endpoint += "?" + req.URL.RawQuery
+	}
+	if name := c.config.Endpoints.fieldFor(endpoint); name != "" {
+		return name
+	}
+	return req.URL.Path
 }
 
 func (c *Client) setCommonHeaders(req *http.Request) {
@@ -152,7 +302,7 @@ func (c *Client) doAuthenticatedRequest(ctx context.Context, endpoint string, to
 	req.Header.Set("Accept", "application/json")
 	c.setCommonHeaders(req)
 
-	resp, err := c.doRequest(req)
+	resp, stats, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +310,7 @@ func (c *Client) doAuthenticatedRequest(ctx context.Context, endpoint string, to
 	// Retry once on 401 with fresh token
 	if resp.StatusCode == http.StatusUnauthorized && !tokenRetry {
 		_ = resp.Body.Close()
+		c.logger.InfoContext(ctx, "nepse access token rejected, refreshing", slog.String("endpoint", endpoint))
 		if err := c.authManager.ForceUpdate(ctx); err != nil {
 			return nil, NewInternalError("failed to refresh token", err)
 		}
@@ -167,42 +318,50 @@ func (c *Client) doAuthenticatedRequest(ctx context.Context, endpoint string, to
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		_ = resp.Body.Close()
-		return nil, MapHTTPStatusToError(resp.StatusCode, resp.Status)
+		return nil, statusError(req, resp, stats)
 	}
 
 	return resp, nil
 }
 
 func (c *Client) apiRequest(ctx context.Context, endpoint string, result any) error {
-	resp, err := c.doAuthenticatedRequest(ctx, endpoint, false)
+	data, err := c.apiRequestRaw(ctx, endpoint)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+	if err := json.Unmarshal(data, result); err != nil {
 		return NewInternalError("failed to decode response", err)
 	}
 	return nil
 }
 
+// apiRequestRaw makes an authenticated GET request and returns raw bytes,
+// serving from the response cache when the endpoint has a TTL configured.
 func (c *Client) apiRequestRaw(ctx context.Context, endpoint string) ([]byte, error) {
-	resp, err := c.doAuthenticatedRequest(ctx, endpoint, false)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
+	key := cacheKey(http.MethodGet, endpoint, nil)
+	return c.cachedFetch(key, c.cacheTTL(endpoint), func() ([]byte, error) {
+		resp, err := c.doAuthenticatedRequest(ctx, endpoint, false)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
 
-	return io.ReadAll(resp.Body)
+		return io.ReadAll(resp.Body)
+	})
 }
 
 // DebugRawRequest makes an authenticated request and returns the raw response.
-// This is for debugging API responses.
+// This is for debugging API responses; use [Get] or [Client.Do] to call
+// endpoints the client doesn't wrap.
 func (c *Client) DebugRawRequest(ctx context.Context, endpoint string) ([]byte, error) {
+	ctx = withOperation(ctx, "DebugRawRequest")
 	return c.apiRequestRaw(ctx, endpoint)
 }
 
+// bodyFunc builds a POST body on every attempt, for bodies derived from the
+// current token that must change when a 401 forces a new one.
+type bodyFunc func(ctx context.Context) (any, error)
+
 // doAuthenticatedPostRequest executes an authenticated POST API request.
 func (c *Client) doAuthenticatedPostRequest(ctx context.Context, endpoint string, body any, tokenRetry bool) (*http.Response, error) {
 	token, err := c.authManager.AccessToken(ctx)
@@ -210,9 +369,16 @@ func (c *Client) doAuthenticatedPostRequest(ctx context.Context, endpoint string
 		return nil, NewInternalError("failed to get access token", err)
 	}
 
+	payload := body
+	if build, ok := body.(bodyFunc); ok {
+		if payload, err = build(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	var bodyReader io.Reader
-	if body != nil {
-		bodyBytes, err := json.Marshal(body)
+	if payload != nil {
+		bodyBytes, err := json.Marshal(payload)
 		if err != nil {
 			return nil, NewInternalError("failed to marshal request body", err)
 		}
@@ -230,7 +396,7 @@ func (c *Client) doAuthenticatedPostRequest(ctx context.Context, endpoint string
 	req.Header.Set("Content-Type", "application/json")
 	c.setCommonHeaders(req)
 
-	resp, err := c.doRequest(req)
+	resp, stats, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -238,6 +404,7 @@ func (c *Client) doAuthenticatedPostRequest(ctx context.Context, endpoint string
 	// Retry once on 401 with fresh token
 	if resp.StatusCode == http.StatusUnauthorized && !tokenRetry {
 		_ = resp.Body.Close()
+		c.logger.InfoContext(ctx, "nepse access token rejected, refreshing", slog.String("endpoint", endpoint))
 		if err := c.authManager.ForceUpdate(ctx); err != nil {
 			return nil, NewInternalError("failed to refresh token", err)
 		}
@@ -245,8 +412,7 @@ func (c *Client) doAuthenticatedPostRequest(ctx context.Context, endpoint string
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		_ = resp.Body.Close()
-		return nil, MapHTTPStatusToError(resp.StatusCode, resp.Status)
+		return nil, statusError(req, resp, stats)
 	}
 
 	return resp, nil
@@ -254,37 +420,59 @@ func (c *Client) doAuthenticatedPostRequest(ctx context.Context, endpoint string
 
 // apiPostRequest makes an authenticated POST request and decodes the JSON response.
 func (c *Client) apiPostRequest(ctx context.Context, endpoint string, body any, result any) error {
-	resp, err := c.doAuthenticatedPostRequest(ctx, endpoint, body, false)
+	data, err := c.apiPostRequestRaw(ctx, endpoint, body)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+	if err := json.Unmarshal(data, result); err != nil {
 		return NewInternalError("failed to decode response", err)
 	}
 	return nil
 }
 
-// apiPostRequestRaw makes an authenticated POST request and returns raw bytes.
+// apiPostRequestRaw makes an authenticated POST request and returns raw bytes,
+// serving from the response cache when the endpoint has a TTL configured.
 func (c *Client) apiPostRequestRaw(ctx context.Context, endpoint string, body any) ([]byte, error) {
-	resp, err := c.doAuthenticatedPostRequest(ctx, endpoint, body, false)
-	if err != nil {
-		return nil, err
+	fetch := func() ([]byte, error) {
+		resp, err := c.doAuthenticatedPostRequest(ctx, endpoint, body, false)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return io.ReadAll(resp.Body)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	return io.ReadAll(resp.Body)
+	ttl := c.cacheTTL(endpoint)
+	if ttl <= 0 {
+		return fetch()
+	}
+	// Key the cache on the body the first attempt would send.
+	key := body
+	if build, ok := body.(bodyFunc); ok {
+		var err error
+		if key, err = build(ctx); err != nil {
+			return nil, err
+		}
+	}
+	bodyBytes, err := json.Marshal(key)
+	if err != nil {
+		return nil, NewInternalError("failed to marshal request body", err)
+	}
+	return c.cachedFetch(cacheKey(http.MethodPost, endpoint, bodyBytes), ttl, fetch)
 }
 
 // DebugRawPostRequest makes an authenticated POST request and returns the raw response.
-// This is for debugging API responses.
+// This is for debugging API responses; use [Post] or [Client.Do] to call
+// endpoints the client doesn't wrap.
 func (c *Client) DebugRawPostRequest(ctx context.Context, endpoint string, body any) ([]byte, error) {
+	ctx = withOperation(ctx, "DebugRawPostRequest")
 	return c.apiPostRequestRaw(ctx, endpoint, body)
 }
 
 // DebugDecodedToken returns the WASM-decoded access token for debugging.
 // This is the token that would be sent in Authorization headers.
 func (c *Client) DebugDecodedToken(ctx context.Context) (string, error) {
+	ctx = withOperation(ctx, "DebugDecodedToken")
 	return c.authManager.AccessToken(ctx)
 }
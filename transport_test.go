@@ -31,6 +31,37 @@ func tokenResponse() auth.TokenResponse {
 	}
 }
 
+// serveToken answers token requests with tokenResponse and passes every
+// other request to next.
+func serveToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/authenticate/prove" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tokenResponse())
+			return
+		}
+		next(w, r)
+	}
+}
+
+// newTestClient returns a client for server that is closed when the test
+// ends. BaseURL and Config are filled in from server; other fields of opts
+// are kept, with HTTPTimeout defaulting to 5s.
+func newTestClient(t *testing.T, server *httptest.Server, opts Options) *Client {
+	t.Helper()
+	opts.BaseURL = server.URL
+	opts.Config = &Config{BaseURL: server.URL, Endpoints: DefaultEndpoints()}
+	if opts.HTTPTimeout == 0 {
+		opts.HTTPTimeout = 5 * time.Second
+	}
+	client, err := NewClient(&opts)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
 func TestClient_TokenFetch(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/api/authenticate/prove" {
@@ -562,3 +593,146 @@ func BenchmarkClient_TokenFetch(b *testing.B) {
 		}
 	}
 }
+
+func TestClient_NativeTokenParser(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/authenticate/prove" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tokenResponse())
+			return
+		}
+		http.NotFound(w, r)
+	})
+	server := newTestServer(handler)
+	defer server.Close()
+
+	decode := func(parser TokenParser) string {
+		t.Helper()
+		client, err := NewClient(&Options{
+			BaseURL:     server.URL,
+			HTTPTimeout: 5 * time.Second,
+			TokenParser: parser,
+			Config: &Config{
+				BaseURL: server.URL,
+			},
+		})
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		defer client.Close()
+
+		token, err := client.DebugDecodedToken(context.Background())
+		if err != nil {
+			t.Fatalf("DebugDecodedToken failed: %v", err)
+		}
+		return token
+	}
+
+	if native, wasm := decode(TokenParserNative), decode(TokenParserWASM); native != wasm {
+		t.Errorf("native parser decoded %q, WASM decoded %q", native, wasm)
+	}
+}
+
+func TestClient_BackgroundRefresh(t *testing.T) {
+	var tokenCalls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/authenticate/prove" {
+			tokenCalls.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tokenResponse())
+			return
+		}
+		http.NotFound(w, r)
+	})
+	server := newTestServer(handler)
+	defer server.Close()
+
+	events := make(chan TokenRefreshEvent, 10)
+	client, err := NewClient(&Options{
+		BaseURL:           server.URL,
+		HTTPTimeout:       5 * time.Second,
+		Config:            &Config{BaseURL: server.URL},
+		BackgroundRefresh: true,
+		OnTokenRefresh:    func(e TokenRefreshEvent) { events <- e },
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	// The first token is fetched without any request being made.
+	select {
+	case e := <-events:
+		if !e.Background || e.Err != nil {
+			t.Errorf("unexpected refresh event %+v", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("background refresher did not fetch a token")
+	}
+	if s := client.TokenStats(); s.Successes != 1 || s.Failures != 0 {
+		t.Errorf("TokenStats() = %+v, want 1 success", s)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	calls := tokenCalls.Load()
+	time.Sleep(20 * time.Millisecond)
+	if tokenCalls.Load() != calls {
+		t.Error("token fetched after Close")
+	}
+}
+
+// fixedClock is a Clock pinned to one instant.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestClient_ClockSkewPayloadDay(t *testing.T) {
+	// 23:55 on 1 January in Kathmandu by the host clock, but NEPSE's clock is
+	// ten minutes ahead and already on 2 January.
+	local := time.Date(2026, 1, 1, 18, 10, 0, 0, time.UTC)
+	server := local.Add(10 * time.Minute)
+
+	var gotID atomic.Int64
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/authenticate/prove":
+			resp := tokenResponse()
+			resp.ServerTime = server.UnixMilli()
+			json.NewEncoder(w).Encode(resp)
+		case "/api/nots/nepse-data/market-open":
+			json.NewEncoder(w).Encode(MarketStatus{IsOpen: "CLOSE", ID: 61})
+		case "/api/nots/market/graphdata/daily/131":
+			var body graphPostPayload
+			json.NewDecoder(r.Body).Decode(&body)
+			gotID.Store(int64(body.ID))
+			w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := newTestServer(handler)
+	defer srv.Close()
+
+	client, err := NewClient(&Options{
+		BaseURL:     srv.URL,
+		HTTPTimeout: 5 * time.Second,
+		Config:      &Config{BaseURL: srv.URL, Endpoints: DefaultEndpoints()},
+		Clock:       fixedClock(local),
+	})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.DailyScripGraph(context.Background(), 131); err != nil {
+		t.Fatalf("DailyScripGraph failed: %v", err)
+	}
+	if want := client.payload.Base(61, 2); gotID.Load() != int64(want) {
+		t.Errorf("payload id = %d, want %d for NEPSE's day 2", gotID.Load(), want)
+	}
+	if got := client.ServerTime(); !got.Equal(server) {
+		t.Errorf("ServerTime() = %v, want %v", got, server)
+	}
+}
@@ -20,9 +20,9 @@ type MarketSummary struct {
 
 // MarketStatus represents the current market status.
 type MarketStatus struct {
-	IsOpen string `json:"isOpen"`
-	AsOf   string `json:"asOf"`
-	ID     int32  `json:"id"`
+	IsOpen string   `json:"isOpen"`
+	AsOf   DateTime `json:"asOf"`
+	ID     int32    `json:"id"`
 }
 
 // IsMarketOpen returns true if the market is currently open.
@@ -32,58 +32,61 @@ func (m *MarketStatus) IsMarketOpen() bool {
 
 // NepseIndexRaw represents the raw NEPSE index response item.
 type NepseIndexRaw struct {
-	ID               int32   `json:"id"`
-	Index            string  `json:"index"`
-	Close            float64 `json:"close"`
-	High             float64 `json:"high"`
-	Low              float64 `json:"low"`
-	PreviousClose    float64 `json:"previousClose"`
-	Change           float64 `json:"change"`
-	PerChange        float64 `json:"perChange"`
-	FiftyTwoWeekHigh float64 `json:"fiftyTwoWeekHigh"`
-	FiftyTwoWeekLow  float64 `json:"fiftyTwoWeekLow"`
-	CurrentValue     float64 `json:"currentValue"`
-	GeneratedTime    string  `json:"generatedTime"`
+	ID               int32    `json:"id"`
+	Index            string   `json:"index"`
+	Close            float64  `json:"close"`
+	High             float64  `json:"high"`
+	Low              float64  `json:"low"`
+	PreviousClose    float64  `json:"previousClose"`
+	Change           float64  `json:"change"`
+	PerChange        float64  `json:"perChange"`
+	FiftyTwoWeekHigh float64  `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekLow  float64  `json:"fiftyTwoWeekLow"`
+	CurrentValue     float64  `json:"currentValue"`
+	GeneratedTime    DateTime `json:"generatedTime"`
 }
 
 // NepseIndex represents the NEPSE main index (ID 58).
 type NepseIndex struct {
-	IndexValue       float64 `json:"close"`
-	PercentChange    float64 `json:"perChange"`
-	PointChange      float64 `json:"change"`
-	High             float64 `json:"high"`
-	Low              float64 `json:"low"`
-	PreviousClose    float64 `json:"previousClose"`
-	FiftyTwoWeekHigh float64 `json:"fiftyTwoWeekHigh"`
-	FiftyTwoWeekLow  float64 `json:"fiftyTwoWeekLow"`
-	CurrentValue     float64 `json:"currentValue"`
-	GeneratedTime    string  `json:"generatedTime"`
+	IndexValue       float64  `json:"close"`
+	PercentChange    float64  `json:"perChange"`
+	PointChange      float64  `json:"change"`
+	High             float64  `json:"high"`
+	Low              float64  `json:"low"`
+	PreviousClose    float64  `json:"previousClose"`
+	FiftyTwoWeekHigh float64  `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekLow  float64  `json:"fiftyTwoWeekLow"`
+	CurrentValue     float64  `json:"currentValue"`
+	GeneratedTime    DateTime `json:"generatedTime"`
 }
 
 // SubIndex represents a sector sub-index.
 type SubIndex struct {
-	ID               int32   `json:"id"`
-	Index            string  `json:"index"`
-	Close            float64 `json:"close"`
-	High             float64 `json:"high"`
-	Low              float64 `json:"low"`
-	PreviousClose    float64 `json:"previousClose"`
-	Change           float64 `json:"change"`
-	PerChange        float64 `json:"perChange"`
-	FiftyTwoWeekHigh float64 `json:"fiftyTwoWeekHigh"`
-	FiftyTwoWeekLow  float64 `json:"fiftyTwoWeekLow"`
-	CurrentValue     float64 `json:"currentValue"`
-	GeneratedTime    string  `json:"generatedTime"`
+	ID               int32    `json:"id"`
+	Index            string   `json:"index"`
+	Close            float64  `json:"close"`
+	High             float64  `json:"high"`
+	Low              float64  `json:"low"`
+	PreviousClose    float64  `json:"previousClose"`
+	Change           float64  `json:"change"`
+	PerChange        float64  `json:"perChange"`
+	FiftyTwoWeekHigh float64  `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekLow  float64  `json:"fiftyTwoWeekLow"`
+	CurrentValue     float64  `json:"currentValue"`
+	GeneratedTime    DateTime `json:"generatedTime"`
 }
 
 // Security represents a listed security/company.
 // Note: The security list API only returns id, symbol, securityName, and activeStatus.
 // For sector info, use GetCompanyList() instead.
+// ISIN is filled in by the [SecurityRegistry] once it has been seen in a
+// [Client.SecurityDetail] response.
 type Security struct {
 	ID           int32  `json:"id"`
 	Symbol       string `json:"symbol"`
 	SecurityName string `json:"securityName"`
 	ActiveStatus string `json:"activeStatus"`
+	ISIN         string `json:"isin,omitempty"`
 }
 
 // Company represents company information.
@@ -116,14 +119,14 @@ type ParsedValue struct {
 
 // ShareGroup represents the share group classification.
 type ShareGroup struct {
-	ID              int32   `json:"id"`
-	Name            string  `json:"name"`
-	Description     string  `json:"description"`
-	CapitalRangeMin int64   `json:"capitalRangeMin"`
-	ModifiedBy      *string `json:"modifiedBy"`
-	ModifiedDate    *string `json:"modifiedDate"`
-	ActiveStatus    string  `json:"activeStatus"`
-	IsDefault       string  `json:"isDefault"`
+	ID              int32    `json:"id"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	CapitalRangeMin int64    `json:"capitalRangeMin"`
+	ModifiedBy      *string  `json:"modifiedBy"`
+	ModifiedDate    DateTime `json:"modifiedDate"`
+	ActiveStatus    string   `json:"activeStatus"`
+	IsDefault       string   `json:"isDefault"`
 }
 
 // SectorMaster represents sector information.
@@ -162,7 +165,7 @@ type TodayPrice struct {
 	DifferenceRs        float64 `json:"differenceRs"`
 	PercentageChange    float64 `json:"percentageChange"`
 	TotalTrades         int32   `json:"totalTrades"`
-	BusinessDate        string  `json:"businessDate"`
+	BusinessDate        Date    `json:"businessDate"`
 	SecurityID          int32   `json:"securityId"`
 	LastTradedPrice     float64 `json:"lastTradedPrice"`
 	MaxPrice            float64 `json:"maxPrice"`
@@ -172,7 +175,7 @@ type TodayPrice struct {
 // PriceHistory represents historical OHLCV data for a security.
 // Note: NEPSE API does not provide open price in historical data.
 type PriceHistory struct {
-	BusinessDate        string  `json:"businessDate"`
+	BusinessDate        Date    `json:"businessDate"`
 	HighPrice           float64 `json:"highPrice"`
 	LowPrice            float64 `json:"lowPrice"`
 	ClosePrice          float64 `json:"closePrice"`
@@ -183,34 +186,25 @@ type PriceHistory struct {
 
 // FloorSheetEntry represents a single floor sheet entry.
 type FloorSheetEntry struct {
-	ContractID       int64   `json:"contractId"`
-	StockSymbol      string  `json:"stockSymbol"`
-	SecurityName     string  `json:"securityName"`
-	BuyerMemberID    int32   `json:"buyerMemberId"`
-	SellerMemberID   int32   `json:"sellerMemberId"`
-	ContractQuantity int64   `json:"contractQuantity"`
-	ContractRate     float64 `json:"contractRate"`
-	BusinessDate     string  `json:"businessDate"`
-	TradeTime        string  `json:"tradeTime"`
-	SecurityID       int32   `json:"securityId"`
-	ContractAmount   float64 `json:"contractAmount"`
-	BuyerBrokerName  string  `json:"buyerBrokerName"`
-	SellerBrokerName string  `json:"sellerBrokerName"`
-	TradeBookID      int64   `json:"tradeBookId"`
+	ContractID       int64    `json:"contractId"`
+	StockSymbol      string   `json:"stockSymbol"`
+	SecurityName     string   `json:"securityName"`
+	BuyerMemberID    int32    `json:"buyerMemberId"`
+	SellerMemberID   int32    `json:"sellerMemberId"`
+	ContractQuantity int64    `json:"contractQuantity"`
+	ContractRate     float64  `json:"contractRate"`
+	BusinessDate     Date     `json:"businessDate"`
+	TradeTime        DateTime `json:"tradeTime"`
+	SecurityID       int32    `json:"securityId"`
+	ContractAmount   float64  `json:"contractAmount"`
+	BuyerBrokerName  string   `json:"buyerBrokerName"`
+	SellerBrokerName string   `json:"sellerBrokerName"`
+	TradeBookID      int64    `json:"tradeBookId"`
 }
 
 // FloorSheetResponse represents the paginated floor sheet response.
 type FloorSheetResponse struct {
-	FloorSheets struct {
-		Content          []FloorSheetEntry `json:"content"`
-		PageNumber       int32             `json:"number"`
-		Size             int32             `json:"size"`
-		TotalElements    int64             `json:"totalElements"`
-		TotalPages       int32             `json:"totalPages"`
-		First            bool              `json:"first"`
-		Last             bool              `json:"last"`
-		NumberOfElements int32             `json:"numberOfElements"`
-	} `json:"floorsheets"`
+	FloorSheets PaginatedResponse[FloorSheetEntry] `json:"floorsheets"`
 }
 
 // DepthEntry represents a single entry in market depth.
@@ -299,7 +293,7 @@ type TopListEntry struct {
 // - Index graphs: [timestamp, value] arrays
 // - Scrip graphs: {"time": timestamp, "value": value} objects
 type GraphDataPoint struct {
-	Timestamp int64
+	Timestamp DateTime
 	Value     float64
 }
 
@@ -309,20 +303,20 @@ func (g *GraphDataPoint) UnmarshalJSON(data []byte) error {
 	// Try array format first (index graphs): [timestamp, value]
 	var arr [2]float64
 	if err := json.Unmarshal(data, &arr); err == nil {
-		g.Timestamp = int64(arr[0])
+		g.Timestamp = dateTimeFromEpoch(arr[0])
 		g.Value = arr[1]
 		return nil
 	}
 
 	// Try object format (scrip graphs): {"time": ..., "value": ...}
 	var obj struct {
-		Time  int64   `json:"time"`
+		Time  float64 `json:"time"`
 		Value float64 `json:"value"`
 	}
 	if err := json.Unmarshal(data, &obj); err != nil {
 		return err
 	}
-	g.Timestamp = obj.Time
+	g.Timestamp = dateTimeFromEpoch(obj.Time)
 	g.Value = obj.Value
 	return nil
 }
@@ -335,19 +329,19 @@ type GraphResponse struct {
 // CompanyDetailsRaw represents the raw nested company details response.
 type CompanyDetailsRaw struct {
 	SecurityMcsData struct {
-		SecurityID          string  `json:"securityId"`
-		OpenPrice           float64 `json:"openPrice"`
-		HighPrice           float64 `json:"highPrice"`
-		LowPrice            float64 `json:"lowPrice"`
-		TotalTradeQuantity  int64   `json:"totalTradeQuantity"`
-		TotalTrades         int32   `json:"totalTrades"`
-		LastTradedPrice     float64 `json:"lastTradedPrice"`
-		PreviousClose       float64 `json:"previousClose"`
-		BusinessDate        string  `json:"businessDate"`
-		ClosePrice          float64 `json:"closePrice"`
-		FiftyTwoWeekHigh    float64 `json:"fiftyTwoWeekHigh"`
-		FiftyTwoWeekLow     float64 `json:"fiftyTwoWeekLow"`
-		LastUpdatedDateTime string  `json:"lastUpdatedDateTime"`
+		SecurityID          string   `json:"securityId"`
+		OpenPrice           float64  `json:"openPrice"`
+		HighPrice           float64  `json:"highPrice"`
+		LowPrice            float64  `json:"lowPrice"`
+		TotalTradeQuantity  int64    `json:"totalTradeQuantity"`
+		TotalTrades         int32    `json:"totalTrades"`
+		LastTradedPrice     float64  `json:"lastTradedPrice"`
+		PreviousClose       float64  `json:"previousClose"`
+		BusinessDate        Date     `json:"businessDate"`
+		ClosePrice          float64  `json:"closePrice"`
+		FiftyTwoWeekHigh    float64  `json:"fiftyTwoWeekHigh"`
+		FiftyTwoWeekLow     float64  `json:"fiftyTwoWeekLow"`
+		LastUpdatedDateTime DateTime `json:"lastUpdatedDateTime"`
 	} `json:"securityMcsData"`
 	SecurityData struct {
 		ID               int32  `json:"id"`
@@ -370,18 +364,18 @@ type CompanyDetails struct {
 	ActiveStatus     string `json:"activeStatus"`
 	PermittedToTrade string `json:"permittedToTrade"`
 
-	OpenPrice           float64 `json:"openPrice"`
-	HighPrice           float64 `json:"highPrice"`
-	LowPrice            float64 `json:"lowPrice"`
-	ClosePrice          float64 `json:"closePrice"`
-	LastTradedPrice     float64 `json:"lastTradedPrice"`
-	PreviousClose       float64 `json:"previousClose"`
-	TotalTradeQuantity  int64   `json:"totalTradeQuantity"`
-	TotalTrades         int32   `json:"totalTrades"`
-	FiftyTwoWeekHigh    float64 `json:"fiftyTwoWeekHigh"`
-	FiftyTwoWeekLow     float64 `json:"fiftyTwoWeekLow"`
-	BusinessDate        string  `json:"businessDate"`
-	LastUpdatedDateTime string  `json:"lastUpdatedDateTime"`
+	OpenPrice           float64  `json:"openPrice"`
+	HighPrice           float64  `json:"highPrice"`
+	LowPrice            float64  `json:"lowPrice"`
+	ClosePrice          float64  `json:"closePrice"`
+	LastTradedPrice     float64  `json:"lastTradedPrice"`
+	PreviousClose       float64  `json:"previousClose"`
+	TotalTradeQuantity  int64    `json:"totalTradeQuantity"`
+	TotalTrades         int32    `json:"totalTrades"`
+	FiftyTwoWeekHigh    float64  `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekLow     float64  `json:"fiftyTwoWeekLow"`
+	BusinessDate        Date     `json:"businessDate"`
+	LastUpdatedDateTime DateTime `json:"lastUpdatedDateTime"`
 }
 
 // SecurityDetailRaw represents the raw response from POST /api/nots/security/{id}.
@@ -395,19 +389,19 @@ type SecurityDetailRaw struct {
 		FaceValue        float64 `json:"faceValue"`
 	} `json:"security"`
 	SecurityDailyTradeDTO struct {
-		SecurityID          string  `json:"securityId"`
-		OpenPrice           float64 `json:"openPrice"`
-		HighPrice           float64 `json:"highPrice"`
-		LowPrice            float64 `json:"lowPrice"`
-		ClosePrice          float64 `json:"closePrice"`
-		TotalTradeQuantity  int64   `json:"totalTradeQuantity"`
-		TotalTrades         int32   `json:"totalTrades"`
-		LastTradedPrice     float64 `json:"lastTradedPrice"`
-		PreviousClose       float64 `json:"previousClose"`
-		FiftyTwoWeekHigh    float64 `json:"fiftyTwoWeekHigh"`
-		FiftyTwoWeekLow     float64 `json:"fiftyTwoWeekLow"`
-		LastUpdatedDateTime string  `json:"lastUpdatedDateTime"`
-		BusinessDate        string  `json:"businessDate"`
+		SecurityID          string   `json:"securityId"`
+		OpenPrice           float64  `json:"openPrice"`
+		HighPrice           float64  `json:"highPrice"`
+		LowPrice            float64  `json:"lowPrice"`
+		ClosePrice          float64  `json:"closePrice"`
+		TotalTradeQuantity  int64    `json:"totalTradeQuantity"`
+		TotalTrades         int32    `json:"totalTrades"`
+		LastTradedPrice     float64  `json:"lastTradedPrice"`
+		PreviousClose       float64  `json:"previousClose"`
+		FiftyTwoWeekHigh    float64  `json:"fiftyTwoWeekHigh"`
+		FiftyTwoWeekLow     float64  `json:"fiftyTwoWeekLow"`
+		LastUpdatedDateTime DateTime `json:"lastUpdatedDateTime"`
+		BusinessDate        Date     `json:"businessDate"`
 	} `json:"securityDailyTradeDto"`
 
 	// Shareholding data at root level
@@ -442,36 +436,36 @@ type SecurityDetail struct {
 	PromoterPercent float64 `json:"promoterPercent"`
 
 	// Price data
-	OpenPrice           float64 `json:"openPrice"`
-	HighPrice           float64 `json:"highPrice"`
-	LowPrice            float64 `json:"lowPrice"`
-	ClosePrice          float64 `json:"closePrice"`
-	LastTradedPrice     float64 `json:"lastTradedPrice"`
-	PreviousClose       float64 `json:"previousClose"`
-	TotalTradedQuantity int64   `json:"totalTradedQuantity"`
-	TotalTrades         int32   `json:"totalTrades"`
-	FiftyTwoWeekHigh    float64 `json:"fiftyTwoWeekHigh"`
-	FiftyTwoWeekLow     float64 `json:"fiftyTwoWeekLow"`
-	BusinessDate        string  `json:"businessDate"`
-	LastUpdatedDateTime string  `json:"lastUpdatedDateTime"`
+	OpenPrice           float64  `json:"openPrice"`
+	HighPrice           float64  `json:"highPrice"`
+	LowPrice            float64  `json:"lowPrice"`
+	ClosePrice          float64  `json:"closePrice"`
+	LastTradedPrice     float64  `json:"lastTradedPrice"`
+	PreviousClose       float64  `json:"previousClose"`
+	TotalTradedQuantity int64    `json:"totalTradedQuantity"`
+	TotalTrades         int32    `json:"totalTrades"`
+	FiftyTwoWeekHigh    float64  `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekLow     float64  `json:"fiftyTwoWeekLow"`
+	BusinessDate        Date     `json:"businessDate"`
+	LastUpdatedDateTime DateTime `json:"lastUpdatedDateTime"`
 }
 
 // LiveMarketEntry represents live market data entry.
 type LiveMarketEntry struct {
-	SecurityID          string  `json:"securityId"`
-	Symbol              string  `json:"symbol"`
-	SecurityName        string  `json:"securityName"`
-	OpenPrice           float64 `json:"openPrice"`
-	HighPrice           float64 `json:"highPrice"`
-	LowPrice            float64 `json:"lowPrice"`
-	LastTradedPrice     float64 `json:"lastTradedPrice"`
-	TotalTradeQuantity  int64   `json:"totalTradeQuantity"`
-	TotalTradeValue     float64 `json:"totalTradeValue"`
-	PreviousClose       float64 `json:"previousClose"`
-	PercentageChange    float64 `json:"percentageChange"`
-	LastTradedVolume    int64   `json:"lastTradedVolume"`
-	LastUpdatedDateTime string  `json:"lastUpdatedDateTime"`
-	AverageTradedPrice  float64 `json:"averageTradedPrice"`
+	SecurityID          string   `json:"securityId"`
+	Symbol              string   `json:"symbol"`
+	SecurityName        string   `json:"securityName"`
+	OpenPrice           float64  `json:"openPrice"`
+	HighPrice           float64  `json:"highPrice"`
+	LowPrice            float64  `json:"lowPrice"`
+	LastTradedPrice     float64  `json:"lastTradedPrice"`
+	TotalTradeQuantity  int64    `json:"totalTradeQuantity"`
+	TotalTradeValue     float64  `json:"totalTradeValue"`
+	PreviousClose       float64  `json:"previousClose"`
+	PercentageChange    float64  `json:"percentageChange"`
+	LastTradedVolume    int64    `json:"lastTradedVolume"`
+	LastUpdatedDateTime DateTime `json:"lastUpdatedDateTime"`
+	AverageTradedPrice  float64  `json:"averageTradedPrice"`
 }
 
 // SectorScrips represents scrips grouped by sector.
@@ -525,7 +519,7 @@ func (b *BoardMember) FullName() string {
 type CorporateAction struct {
 	ActiveStatus          string   `json:"activeStatus"`
 	AuthorizationComments *string  `json:"authorizationComments"`
-	SubmittedDate         string   `json:"submittedDate"`
+	SubmittedDate         DateTime `json:"submittedDate"`
 	FilePath              string   `json:"filePath"`
 	DocumentID            int32    `json:"documentId"`
 	RatioNum              float64  `json:"ratioNum"`
@@ -590,17 +584,17 @@ type FiscalReport struct {
 
 // ReportDocument represents a document attached to a report.
 type ReportDocument struct {
-	ID            int32  `json:"id"`
-	SubmittedDate string `json:"submittedDate"`
-	FilePath      string `json:"filePath"`
-	EncryptedID   string `json:"encryptedId"`
+	ID            int32    `json:"id"`
+	SubmittedDate DateTime `json:"submittedDate"`
+	FilePath      string   `json:"filePath"`
+	EncryptedID   string   `json:"encryptedId"`
 }
 
 // Report represents a quarterly or annual financial report.
 type Report struct {
 	ID                             int32            `json:"id"`
 	ActiveStatus                   string           `json:"activeStatus"`
-	ModifiedDate                   string           `json:"modifiedDate"`
+	ModifiedDate                   DateTime         `json:"modifiedDate"`
 	ApplicationType                int32            `json:"applicationType"`
 	ApplicationStatus              int32            `json:"applicationStatus"`
 	FiscalReport                   *FiscalReport    `json:"fiscalReport"`
@@ -648,7 +642,7 @@ type CompanyNews struct {
 	NewsHeadline    string          `json:"newsHeadline"`
 	NewsBody        string          `json:"newsBody"`
 	NewsType        string          `json:"newsType"`
-	ExpiryDate      string          `json:"expiryDate"`
+	ExpiryDate      DateTime        `json:"expiryDate"`
 	DividendsNotice *DividendNotice `json:"dividendsNotice"`
 }
 
@@ -656,7 +650,7 @@ type CompanyNews struct {
 type Dividend struct {
 	ID                int32        `json:"id"`
 	ActiveStatus      string       `json:"activeStatus"`
-	ModifiedDate      string       `json:"modifiedDate"`
+	ModifiedDate      DateTime     `json:"modifiedDate"`
 	ApplicationType   int32        `json:"applicationType"`
 	ApplicationStatus int32        `json:"applicationStatus"`
 	CompanyNews       *CompanyNews `json:"companyNews"`